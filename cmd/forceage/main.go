@@ -0,0 +1,89 @@
+// Command forceage makes a customer's journey appear to have been inactive
+// for a given duration, so expiry and repique eligibility can be checked on
+// the next worker run without waiting. It moves the journey state's last
+// interaction and step start back to now minus the age, keeping its TTL.
+//
+// Usage:
+//
+//	forceage -journey checkout -customer 5511999990000 -age 2h
+//
+// It refuses to run unless ALLOW_STATE_AGING=true, which must never be set in
+// production. It exits 0 on success and 2 on error.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"worker-project/internal/adapters/redis"
+	"worker-project/internal/config"
+	"worker-project/internal/logging"
+	"worker-project/internal/ports"
+)
+
+// errAgingDisabled is returned when ALLOW_STATE_AGING is not set.
+var errAgingDisabled = errors.New("state aging disabled (set ALLOW_STATE_AGING=true outside production)")
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	journeyID := flag.String("journey", "", "journey ID")
+	customerNumber := flag.String("customer", "", "customer number")
+	age := flag.Duration("age", 0, "inactivity to simulate (e.g. 90m)")
+	flag.Parse()
+
+	if *journeyID == "" || *customerNumber == "" || *age <= 0 {
+		flag.Usage()
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	logCfg := logging.DefaultConfig()
+	logCfg.Output = os.Stderr
+	logger := logging.New(logCfg)
+
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		return 2
+	}
+	if !cfg.Worker.AllowStateAging {
+		logger.Error("refusing to age journey state", "error", errAgingDisabled)
+		return 2
+	}
+
+	client, err := redis.NewClient(ctx, cfg.Redis, logger.With("component", "redis"))
+	if err != nil {
+		logger.Error("failed to connect to redis", "error", err)
+		return 2
+	}
+	defer client.Close()
+
+	repository := redis.NewRepository(client, redis.NewKeyBuilder(cfg.Redis.KeyPrefix), cfg.Worker.DefaultStateTTL, logger.With("component", "repository"))
+
+	if err := forceAge(ctx, cfg.Worker, repository, *journeyID, *customerNumber, *age); err != nil {
+		logger.Error("failed to age journey state", "journey_id", *journeyID, "customer_number", *customerNumber, "error", err)
+		return 2
+	}
+
+	fmt.Printf("aged %s/%s by %s\n", *journeyID, *customerNumber, *age)
+	return 0
+}
+
+// forceAge ages a journey state, provided state aging is allowed.
+func forceAge(ctx context.Context, cfg config.WorkerConfig, ager ports.JourneyAger, journeyID, customerNumber string, age time.Duration) error {
+	if !cfg.AllowStateAging {
+		return errAgingDisabled
+	}
+	return ager.ForceAge(ctx, journeyID, customerNumber, age)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"worker-project/internal/config"
+)
+
+// recordingAger records the journeys it is asked to age.
+type recordingAger struct {
+	aged []string
+}
+
+func (a *recordingAger) ForceAge(_ context.Context, journeyID, customerNumber string, age time.Duration) error {
+	a.aged = append(a.aged, journeyID+":"+customerNumber+":"+age.String())
+	return nil
+}
+
+func TestForceAgeRequiresNonProdFlag(t *testing.T) {
+	ager := &recordingAger{}
+
+	err := forceAge(context.Background(), config.WorkerConfig{}, ager, "checkout", "5511999990000", time.Hour)
+	if !errors.Is(err, errAgingDisabled) {
+		t.Fatalf("expected errAgingDisabled, got: %v", err)
+	}
+	if len(ager.aged) != 0 {
+		t.Fatalf("journey aged without ALLOW_STATE_AGING: %v", ager.aged)
+	}
+
+	if err := forceAge(context.Background(), config.WorkerConfig{AllowStateAging: true}, ager, "checkout", "5511999990000", time.Hour); err != nil {
+		t.Fatalf("forceAge: %v", err)
+	}
+	if len(ager.aged) != 1 || ager.aged[0] != "checkout:5511999990000:1h0m0s" {
+		t.Fatalf("aged = %v", ager.aged)
+	}
+}
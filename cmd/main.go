@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,16 +10,41 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 
 	"worker-project/internal/adapters/appconfig"
+	"worker-project/internal/adapters/decisions"
+	"worker-project/internal/adapters/events"
+	"worker-project/internal/adapters/featureflags"
 	"worker-project/internal/adapters/messaging"
 	"worker-project/internal/adapters/redis"
+	"worker-project/internal/adapters/shortener"
 	"worker-project/internal/app"
 	"worker-project/internal/config"
 	"worker-project/internal/logging"
+	"worker-project/internal/metrics"
+	"worker-project/internal/ports"
 )
 
+// process holds the dependencies that outlive a single run. In Lambda they
+// are reused by every invocation handled by the same execution environment,
+// so Redis connections, journey config caches and last-known-good fallbacks
+// carry across runs.
+type process struct {
+	cfg          *config.AppConfig
+	logger       *slog.Logger
+	emitter      *metrics.EMFEmitter
+	redisClient  *redis.Client
+	readClient   *redis.Client
+	keys         redis.KeyBuilder
+	templates    *appconfig.TemplateRenderer
+	configLoader *appconfig.Loader
+}
+
 func main() {
 	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" {
-		lambda.Start(handleLambda)
+		p, err := newProcess(context.Background())
+		if err != nil {
+			os.Exit(1)
+		}
+		lambda.Start(p.run)
 	} else {
 		if err := runLocal(); err != nil {
 			os.Exit(1)
@@ -26,45 +52,176 @@ func main() {
 	}
 }
 
-func handleLambda(ctx context.Context) error {
-	return run(ctx)
-}
-
 func runLocal() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
-	return run(ctx)
+
+	p, err := newProcess(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.close()
+
+	return p.run(ctx)
 }
 
-func run(ctx context.Context) error {
+func newProcess(ctx context.Context) (*process, error) {
 	logger := logging.New(logging.DefaultConfig())
 
 	cfg, err := config.LoadFromEnv()
 	if err != nil {
 		logger.Error("failed to load config", "error", err)
-		return err
+		return nil, err
 	}
 
-	redisClient, err := redis.NewClient(cfg.Redis)
+	redisClient, err := redis.NewClient(ctx, cfg.Redis, logger.With("component", "redis"))
 	if err != nil {
 		logger.Error("failed to connect to redis", "error", err)
-		return err
+		return nil, err
 	}
-	defer redisClient.Close()
 
 	logger.Info("connected to redis", "addr", cfg.Redis.Addr)
 
-	templateRenderer := appconfig.NewTemplateRenderer(cfg.AppConfig, logger.With("component", "templates"))
-	configLoader := appconfig.NewLoader(cfg.AppConfig, logger.With("component", "config_loader"))
-	messengerClient := messaging.NewClient(templateRenderer, logger.With("component", "messenger"))
+	readClient, err := redis.NewReadClient(ctx, cfg.Redis, redisClient, logger.With("component", "redis"))
+	if err != nil {
+		logger.Error("failed to connect to redis read replica", "error", err)
+		redisClient.Close()
+		return nil, err
+	}
+	if readClient != redisClient {
+		logger.Info("connected to redis read replica", "addr", cfg.Redis.ReadAddr)
+	}
+
+	var emitter *metrics.EMFEmitter
+	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" {
+		emitter = metrics.NewEMFEmitter(os.Stdout, "RecoveryWorker")
+	}
+
+	keys := redis.NewKeyBuilder(cfg.Redis.KeyPrefix)
+	templates := appconfig.NewTemplateRenderer(cfg.AppConfig, logger.With("component", "templates"))
+
+	return &process{
+		cfg:         cfg,
+		logger:      logger,
+		emitter:     emitter,
+		redisClient: redisClient,
+		readClient:  readClient,
+		keys:        keys,
+		templates:   templates,
+		configLoader: appconfig.NewLoader(
+			cfg.AppConfig,
+			templates,
+			redis.NewConfigHashStore(redisClient, keys),
+			emitter,
+			logger.With("component", "config_loader"),
+		),
+	}, nil
+}
+
+// close releases the process's Redis connections.
+func (p *process) close() {
+	if p.readClient != p.redisClient {
+		p.readClient.Close()
+	}
+	p.redisClient.Close()
+}
+
+func (p *process) run(ctx context.Context) error {
+	cfg, logger, emitter := p.cfg, p.logger, p.emitter
+	redisClient, readClient, keys := p.redisClient, p.readClient, p.keys
+	configLoader := p.configLoader
+
+	// Templates are refetched every run; only journey configs are cached
+	// across runs.
+	templateRenderer := p.templates
+	templateRenderer.ClearCache()
+
+	var shortenerClient ports.URLShortener
+	if cfg.Shortener.Endpoint != "" {
+		shortenerClient = shortener.NewHTTPShortener(cfg.Shortener, logger.With("component", "shortener"))
+	}
+
+	var spendLimiter *messaging.SpendLimiter
+	if cfg.Spend.Enabled() {
+		spendLimiter = messaging.NewSpendLimiter(
+			redis.NewSpendLedger(redisClient, keys),
+			cfg.Spend,
+			logger.With("component", "spend"),
+		)
+	}
+
+	var signer *messaging.Signer
+	if len(cfg.Signing.Keys) > 0 {
+		signer = messaging.NewSigner(cfg.Signing.Keys)
+	}
+
+	messengerClient := messaging.NewClient(
+		templateRenderer,
+		shortenerClient,
+		spendLimiter,
+		signer,
+		logger.With("component", "messenger"),
+	)
+
+	var flags ports.FeatureFlagEvaluator = featureflags.NoopEvaluator{}
+	if cfg.FeatureFlags.Endpoint != "" {
+		flags = featureflags.NewHTTPEvaluator(cfg.FeatureFlags, logger.With("component", "feature_flags"))
+	}
+
+	var eventPublisher ports.EventPublisher = events.NoopPublisher{}
+	if cfg.Worker.PublishEvents {
+		eventPublisher = redis.NewEventStream(redisClient, keys)
+	}
+
+	var decisionLog ports.DecisionLog = decisions.NoopLog{}
+	if cfg.Worker.DecisionLogPath != "" {
+		jsonlLog, err := decisions.NewJSONLLog(cfg.Worker.DecisionLogPath, cfg.Worker.DecisionLogSalt)
+		if err != nil {
+			logger.Error("failed to open decision log", "error", err)
+			return err
+		}
+		defer jsonlLog.Close()
+		decisionLog = jsonlLog
+	}
+
+	var customerLocker ports.CustomerLocker
+	if cfg.Worker.CustomerLock {
+		customerLocker = redis.NewCustomerLock(redisClient, keys, cfg.Worker.CustomerLockTTL, logger.With("component", "customer_lock"))
+	}
+
+	healthChecks := []app.HealthCheck{
+		{Name: "redis", Check: redisClient.Ping},
+		{Name: "appconfig", Check: configLoader.Ping},
+	}
+	for _, journeyID := range cfg.Worker.StartupCheckJourneys {
+		journeyID := journeyID
+		healthChecks = append(healthChecks, app.HealthCheck{
+			Name: "journey config " + journeyID,
+			Check: func(ctx context.Context) error {
+				_, err := configLoader.LoadJourneyConfig(ctx, journeyID)
+				return err
+			},
+		})
+	}
 
 	application := app.New(app.Options{
-		Config:       cfg,
-		Logger:       logger,
-		Scanner:      redis.NewScanner(redisClient, cfg.Worker.ScanCount, logger.With("component", "scanner")),
-		Repository:   redis.NewRepository(redisClient, cfg.Worker.DefaultStateTTL),
-		ConfigLoader: configLoader,
-		Messenger:    messengerClient,
+		Config:         cfg,
+		Logger:         logger,
+		Scanner:        redis.NewScanner(readClient, keys, cfg.Worker.ScanCount, cfg.Worker.ScanMaxDuration, logger.With("component", "scanner")),
+		Repository:     redis.NewRepository(redisClient, keys, cfg.Worker.DefaultStateTTL, logger.With("component", "repository")),
+		ConfigLoader:   configLoader,
+		Templates:      templateRenderer,
+		Messenger:      messengerClient,
+		KillSwitch:     redis.NewKillSwitch(redisClient, keys, cfg.Worker.KillSwitchCacheTTL),
+		FeatureFlags:   flags,
+		Events:         eventPublisher,
+		Decisions:      decisionLog,
+		CustomerLocker: customerLocker,
+		Metrics:        emitter,
+		HealthChecks:   healthChecks,
+		ScanBaseline:   redis.NewScanBaseline(redisClient, keys, cfg.Worker.ScanBaselineRuns),
+		RunTracker:     redis.NewRunTracker(redisClient, keys),
+		JourneyTracker: redis.NewJourneyTracker(redisClient, keys),
 	})
 
 	return application.Run(ctx)
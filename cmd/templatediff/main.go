@@ -0,0 +1,109 @@
+// Command templatediff previews a template change. It renders a template
+// reference from the deployed AppConfig and from a candidate template config
+// file against the same sample metadata, and prints a unified diff of the
+// two bodies.
+//
+// Usage:
+//
+//	templatediff -ref journey.x.templates:reminder -candidate templates.yaml -metadata sample.json
+//
+// It exits 0 when the bodies match, 1 when they differ and 2 on error.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"worker-project/internal/adapters/appconfig"
+	"worker-project/internal/config"
+	"worker-project/internal/logging"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	ref := flag.String("ref", "", "template reference (config_name:template_key)")
+	candidatePath := flag.String("candidate", "", "candidate template config YAML file")
+	metadataPath := flag.String("metadata", "", "sample metadata JSON file (optional)")
+	flag.Parse()
+
+	if *ref == "" || *candidatePath == "" {
+		flag.Usage()
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	logCfg := logging.DefaultConfig()
+	logCfg.Output = os.Stderr
+	logger := logging.New(logCfg)
+
+	current, candidate, err := render(ctx, *ref, *candidatePath, *metadataPath, logger)
+	if err != nil {
+		logger.Error("failed to render template", "template", *ref, "error", err)
+		return 2
+	}
+
+	if current == candidate {
+		fmt.Println("no differences")
+		return 0
+	}
+
+	fmt.Print(unifiedDiff("current", "candidate", current, candidate))
+	return 1
+}
+
+// render renders the deployed and the candidate version of a template.
+func render(ctx context.Context, ref, candidatePath, metadataPath string, logger *slog.Logger) (string, string, error) {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		return "", "", fmt.Errorf("load config: %w", err)
+	}
+
+	metadata := map[string]any{}
+	if metadataPath != "" {
+		data, err := os.ReadFile(metadataPath)
+		if err != nil {
+			return "", "", fmt.Errorf("read metadata: %w", err)
+		}
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return "", "", fmt.Errorf("parse metadata: %w", err)
+		}
+	}
+
+	candidateData, err := os.ReadFile(candidatePath)
+	if err != nil {
+		return "", "", fmt.Errorf("read candidate: %w", err)
+	}
+
+	renderer := appconfig.NewTemplateRenderer(cfg.AppConfig, logger.With("component", "templates"))
+
+	currentTemplate, err := renderer.LoadTemplate(ctx, ref)
+	if err != nil {
+		return "", "", fmt.Errorf("load current template: %w", err)
+	}
+	candidateTemplate, err := appconfig.LoadCandidateTemplate(ref, candidateData)
+	if err != nil {
+		return "", "", fmt.Errorf("load candidate template: %w", err)
+	}
+
+	current, err := renderer.Render(currentTemplate, metadata)
+	if err != nil {
+		return "", "", fmt.Errorf("render current template: %w", err)
+	}
+	candidate, err := renderer.Render(candidateTemplate, metadata)
+	if err != nil {
+		return "", "", fmt.Errorf("render candidate template: %w", err)
+	}
+
+	return current, candidate, nil
+}
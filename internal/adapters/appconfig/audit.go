@@ -0,0 +1,87 @@
+package appconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"worker-project/internal/config"
+)
+
+// configHash returns a hash of the effective journey configuration, after
+// defaults are inherited, so formatting-only edits do not count as changes.
+func configHash(cfg *config.JourneyConfig) string {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// changedSections lists the top-level sections that differ between two configs.
+func changedSections(old, cur *config.JourneyConfig) []string {
+	var changed []string
+	if !reflect.DeepEqual(old.Journey, cur.Journey) {
+		changed = append(changed, "journey")
+	}
+	if !reflect.DeepEqual(old.Settings, cur.Settings) {
+		changed = append(changed, "settings")
+	}
+	if !reflect.DeepEqual(old.Steps, cur.Steps) {
+		changed = append(changed, "steps")
+	}
+	return changed
+}
+
+// auditConfigChange records the first load of a journey configuration and
+// every later change to it, comparing against the hash stored by the previous
+// load so changes are detected across runs and processes. It is a no-op
+// without a hash store.
+func (l *Loader) auditConfigChange(ctx context.Context, journeyID string, cfg *config.JourneyConfig) {
+	if l.hashes == nil {
+		return
+	}
+
+	hash := configHash(cfg)
+	oldHash, seen, err := l.hashes.SwapConfigHash(ctx, journeyID, hash)
+	if err != nil {
+		l.logger.Warn("failed to audit journey config", "journey_id", journeyID, "error", err)
+		return
+	}
+
+	if !seen {
+		l.logger.Info("journey config audit",
+			"event", "first_load",
+			"journey_id", journeyID,
+			"hash", hash,
+			"at", time.Now().UTC(),
+		)
+		return
+	}
+
+	if hash == oldHash {
+		return
+	}
+
+	attrs := []any{
+		"event", "changed",
+		"journey_id", journeyID,
+		"old_hash", oldHash,
+		"new_hash", hash,
+	}
+
+	// Sections can only be diffed against a config this process loaded.
+	l.mu.RLock()
+	old, ok := l.lastGood[journeyID]
+	l.mu.RUnlock()
+	if ok {
+		attrs = append(attrs, "changed_sections", changedSections(old, cfg))
+	}
+
+	l.logger.Info("journey config audit", append(attrs, "at", time.Now().UTC())...)
+}
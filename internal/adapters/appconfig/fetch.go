@@ -0,0 +1,94 @@
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"worker-project/internal/config"
+)
+
+// fetcher performs AppConfig GETs bounded by a per-attempt timeout, retrying
+// connection errors and 5xx responses with exponential backoff so a transient
+// blip does not fail every send of a journey.
+type fetcher struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	logger     *slog.Logger
+}
+
+func newFetcher(cfg config.AppConfigSettings, logger *slog.Logger) fetcher {
+	return fetcher{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		timeout: cfg.FetchTimeout,
+		retries: cfg.FetchRetries,
+		backoff: cfg.FetchBackoff,
+		logger:  logger,
+	}
+}
+
+// get fetches url and returns the body and status of the last attempt.
+// A non-nil error means no response was received.
+func (f fetcher) get(ctx context.Context, url string) ([]byte, int, error) {
+	backoff := f.backoff
+	for attempt := 0; ; attempt++ {
+		data, status, err := f.getOnce(ctx, url)
+
+		retryable := (err != nil && ctx.Err() == nil) || status >= http.StatusInternalServerError
+		if !retryable || attempt >= f.retries {
+			return data, status, err
+		}
+
+		f.logger.Warn("appconfig fetch failed, retrying",
+			"url", url,
+			"attempt", attempt+1,
+			"status", status,
+			"error", err,
+			"backoff", backoff,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (f fetcher) getOnce(ctx context.Context, url string) ([]byte, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			f.logger.Warn("failed to close response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("read response body: %w", err)
+	}
+	return data, resp.StatusCode, nil
+}
@@ -0,0 +1,165 @@
+package appconfig
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// templateFields returns the metadata field paths a template references from
+// the root data, e.g. "customer.name" for {{.customer.name}}. Conditions of
+// if, range and with are optional by design and are skipped, as are fields
+// inside range and with blocks, which are relative to a different dot.
+func templateFields(t *template.Template) []string {
+	seen := make(map[string]bool)
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil {
+			collectFields(tmpl.Tree.Root, seen)
+		}
+	}
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func collectFields(node parse.Node, seen map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, seen)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, seen)
+	case *parse.IfNode:
+		collectFields(n.List, seen)
+		collectFields(n.ElseList, seen)
+	case *parse.RangeNode:
+		collectFields(n.ElseList, seen)
+	case *parse.WithNode:
+		collectFields(n.ElseList, seen)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, seen)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, seen)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, seen)
+		}
+	case *parse.FieldNode:
+		seen[strings.Join(n.Ident, ".")] = true
+	case *parse.VariableNode:
+		// $.field refers to the root data from anywhere in the template.
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			seen[strings.Join(n.Ident[1:], ".")] = true
+		}
+	}
+}
+
+// missingFields returns the field paths that are absent from metadata.
+// Paths through values other than maps cannot be checked and are assumed present.
+func missingFields(fields []string, metadata map[string]any) []string {
+	var missing []string
+	for _, field := range fields {
+		var current any = metadata
+		for _, key := range strings.Split(field, ".") {
+			m, ok := current.(map[string]any)
+			if !ok {
+				break
+			}
+			if current, ok = m[key]; !ok || current == nil {
+				missing = append(missing, field)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// renderKeyFields returns every metadata field path a template can read,
+// including conditions. It reports false when the set cannot be known
+// statically: when the template reads the whole data, or moves the dot
+// with range, with or a template call.
+func renderKeyFields(t *template.Template) ([]string, bool) {
+	seen := make(map[string]bool)
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil && !collectKeyFields(tmpl.Tree.Root, seen) {
+			return nil, false
+		}
+	}
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, true
+}
+
+func collectKeyFields(node parse.Node, seen map[string]bool) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return true
+		}
+		for _, child := range n.Nodes {
+			if !collectKeyFields(child, seen) {
+				return false
+			}
+		}
+		return true
+	case *parse.ActionNode:
+		return collectKeyFields(n.Pipe, seen)
+	case *parse.IfNode:
+		return collectKeyFields(n.Pipe, seen) &&
+			collectKeyFields(n.List, seen) &&
+			collectKeyFields(n.ElseList, seen)
+	case *parse.PipeNode:
+		if n == nil {
+			return true
+		}
+		for _, cmd := range n.Cmds {
+			if !collectKeyFields(cmd, seen) {
+				return false
+			}
+		}
+		return true
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if !collectKeyFields(arg, seen) {
+				return false
+			}
+		}
+		return true
+	case *parse.FieldNode:
+		seen[strings.Join(n.Ident, ".")] = true
+		return true
+	case *parse.VariableNode:
+		if n.Ident[0] != "$" {
+			return true // declared from a pipeline whose fields are collected
+		}
+		if len(n.Ident) == 1 {
+			return false
+		}
+		seen[strings.Join(n.Ident[1:], ".")] = true
+		return true
+	case *parse.TextNode, *parse.CommentNode, *parse.StringNode, *parse.NumberNode,
+		*parse.BoolNode, *parse.NilNode, *parse.IdentifierNode:
+		return true
+	default:
+		// Dot, chains, range, with, template calls and anything newer.
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+package appconfig
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent calls for the same key so that callers
+// share a single in-flight fetch instead of each issuing their own.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which case
+// it waits for that call and returns its result. A waiter whose context is
+// cancelled stops waiting and returns the context error; the shared call
+// carries on for the others.
+func (g *flightGroup[T]) Do(ctx context.Context, key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.val, call.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+
+	call := &flightCall[T]{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.val, call.err = fn()
+	close(call.done)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.val, call.err
+}
@@ -0,0 +1,131 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// dateLayouts are the layouts accepted when coercing a string into a date.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// templateFuncs returns the helper functions available to message templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"currency": formatCurrency,
+		"date":     formatDate,
+		"number":   formatNumber,
+	}
+}
+
+// formatCurrency formats a value as Brazilian Real (e.g., "R$ 1.234,50").
+func formatCurrency(v any) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", fmt.Errorf("currency: %w", err)
+	}
+
+	// The sign is applied after rounding so that amounts rounding to zero
+	// do not render as "-R$ 0,00".
+	cents := int64(math.Round(math.Abs(f) * 100))
+	sign := ""
+	if f < 0 && cents > 0 {
+		sign = "-"
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, groupThousands(cents/100), cents%100), nil
+}
+
+// formatDate coerces a value into a time and formats it with the given layout.
+func formatDate(v any, layout string) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", fmt.Errorf("date: %w", err)
+	}
+	return t.Format(layout), nil
+}
+
+// formatNumber renders a number without exponent notation, dropping the
+// fractional part when the value is integral (JSON numbers decode as float64).
+func formatNumber(v any) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", fmt.Errorf("number: %w", err)
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatInt(int64(f), 10), nil
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
+
+// groupThousands formats an integer using "." as the thousands separator.
+func groupThousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if len(s) <= 3 {
+		return s
+	}
+
+	var b strings.Builder
+	head := len(s) % 3
+	if head > 0 {
+		b.WriteString(s[:head])
+	}
+	for i := head; i < len(s); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(s[i : i+3])
+	}
+	return b.String()
+}
+
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(n), 64)
+	case nil:
+		return 0, fmt.Errorf("missing value")
+	default:
+		return 0, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range dateLayouts {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognized date format: %s", t)
+	case nil:
+		return time.Time{}, fmt.Errorf("missing value")
+	default:
+		return time.Time{}, fmt.Errorf("unsupported type %T", v)
+	}
+}
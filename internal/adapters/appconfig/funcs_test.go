@@ -0,0 +1,115 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "thousands", value: 1234.5, want: "R$ 1.234,50"},
+		{name: "millions", value: 1234567.891, want: "R$ 1.234.567,89"},
+		{name: "below one", value: 0.5, want: "R$ 0,50"},
+		{name: "zero", value: 0, want: "R$ 0,00"},
+		{name: "negative", value: -1234.5, want: "-R$ 1.234,50"},
+		{name: "negative rounding to zero", value: -0.004, want: "R$ 0,00"},
+		{name: "rounds half up", value: 10.005, want: "R$ 10,01"},
+		{name: "integral float64", value: float64(150), want: "R$ 150,00"},
+		{name: "int", value: 42, want: "R$ 42,00"},
+		{name: "json number", value: json.Number("99.9"), want: "R$ 99,90"},
+		{name: "numeric string", value: " 1000 ", want: "R$ 1.000,00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatCurrency(tt.value)
+			if err != nil {
+				t.Fatalf("formatCurrency(%v): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatCurrency(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCurrencyRejectsInvalidValues(t *testing.T) {
+	for _, value := range []any{nil, "abc", []int{1}} {
+		if got, err := formatCurrency(value); err == nil {
+			t.Errorf("formatCurrency(%v) = %q, want an error", value, got)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "integral float64", value: float64(1234), want: "1234"},
+		{name: "large integral float64", value: 1e12, want: "1000000000000"},
+		{name: "fractional", value: 12.75, want: "12.75"},
+		{name: "small fraction", value: 0.000001, want: "0.000001"},
+		{name: "negative integral", value: float64(-5), want: "-5"},
+		{name: "beyond int64 precision", value: 1e20, want: "100000000000000000000"},
+		{name: "int", value: 7, want: "7"},
+		{name: "json number", value: json.Number("3.0"), want: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatNumber(tt.value)
+			if err != nil {
+				t.Fatalf("formatNumber(%v): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatNumber(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "RFC3339Nano", value: "2026-03-14T09:30:00.123456789Z", want: "14/03/2026 09:30"},
+		{name: "RFC3339", value: "2026-03-14T09:30:00-03:00", want: "14/03/2026 09:30"},
+		{name: "ISO without zone", value: "2026-03-14T09:30:00", want: "14/03/2026 09:30"},
+		{name: "space separated", value: "2026-03-14 09:30:00", want: "14/03/2026 09:30"},
+		{name: "date only", value: "2026-03-14", want: "14/03/2026 00:00"},
+		{name: "time.Time", value: time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC), want: "14/03/2026 09:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatDate(tt.value, "02/01/2006 15:04")
+			if err != nil {
+				t.Fatalf("formatDate(%v): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatDate(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if len(tests)-1 != len(dateLayouts) {
+		t.Errorf("%d string cases for %d date layouts", len(tests)-1, len(dateLayouts))
+	}
+}
+
+func TestFormatDateRejectsInvalidValues(t *testing.T) {
+	for _, value := range []any{nil, "14/03/2026", 1710408600} {
+		if got, err := formatDate(value, time.RFC3339); err == nil {
+			t.Errorf("formatDate(%v) = %q, want an error", value, got)
+		}
+	}
+}
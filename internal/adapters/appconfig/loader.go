@@ -1,86 +1,312 @@
 package appconfig
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"worker-project/internal/config"
+	"worker-project/internal/domain"
+	"worker-project/internal/metrics"
+	"worker-project/internal/ports"
 )
 
+// defaultJourneyProfile is the profile whose settings every journey inherits
+// when inheritance is enabled.
+const defaultJourneyProfile = "journey.default"
+
 // Loader implements ports.JourneyConfigLoader using AWS AppConfig.
+// It is meant to live for the whole process, so that cached and
+// last-known-good configurations survive across runs.
 type Loader struct {
-	httpClient *http.Client
-	endpoint   string
-	logger     *slog.Logger
-	cache      map[string]*config.JourneyConfig
+	fetch           fetcher
+	endpoint        string
+	inheritDefaults bool
+	cacheTTL        time.Duration
+	templates       ports.TemplateRenderer
+	hashes          ports.ConfigHashStore
+	metrics         *metrics.EMFEmitter
+	logger          *slog.Logger
+	now             func() time.Time
+
+	mu       sync.RWMutex
+	cache    map[string]cachedConfig
+	lastGood map[string]*config.JourneyConfig
+	flight   flightGroup[*config.JourneyConfig]
+}
+
+// cachedConfig is a journey configuration served until expiresAt.
+type cachedConfig struct {
+	cfg       *config.JourneyConfig
+	expiresAt time.Time
 }
 
-// NewLoader creates a new AppConfig loader.
-func NewLoader(cfg config.AppConfigSettings, logger *slog.Logger) *Loader {
+// NewLoader creates a new AppConfig loader. Attempt templates are checked
+// only when templates is non-nil, and config changes audited only when hashes
+// is non-nil; the emitter may be nil.
+func NewLoader(
+	cfg config.AppConfigSettings,
+	templates ports.TemplateRenderer,
+	hashes ports.ConfigHashStore,
+	emitter *metrics.EMFEmitter,
+	logger *slog.Logger,
+) *Loader {
 	return &Loader{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		endpoint: cfg.Endpoint,
-		logger:   logger,
-		cache:    make(map[string]*config.JourneyConfig),
+		fetch:           newFetcher(cfg, logger),
+		endpoint:        cfg.Endpoint,
+		inheritDefaults: cfg.InheritDefaults,
+		cacheTTL:        cfg.CacheTTL,
+		templates:       templates,
+		hashes:          hashes,
+		metrics:         emitter,
+		logger:          logger,
+		now:             time.Now,
+		cache:           make(map[string]cachedConfig),
+		lastGood:        make(map[string]*config.JourneyConfig),
 	}
 }
 
 // LoadJourneyConfig loads configuration for a specific journey.
-func (l *Loader) LoadJourneyConfig(journeyID string) (*config.JourneyConfig, error) {
-	if cached, ok := l.cache[journeyID]; ok {
-		return cached, nil
+// Concurrent loads of the same uncached journey share a single fetch.
+func (l *Loader) LoadJourneyConfig(ctx context.Context, journeyID string) (*config.JourneyConfig, error) {
+	l.mu.RLock()
+	cached, ok := l.cache[journeyID]
+	l.mu.RUnlock()
+	if ok && l.now().Before(cached.expiresAt) {
+		return cached.cfg, nil
+	}
+
+	return l.flight.Do(ctx, journeyID, func() (*config.JourneyConfig, error) {
+		return l.reloadJourneyConfig(ctx, journeyID)
+	})
+}
+
+// reloadJourneyConfig fetches a journey configuration and updates the cache,
+// falling back to the last known good configuration on failure.
+func (l *Loader) reloadJourneyConfig(ctx context.Context, journeyID string) (*config.JourneyConfig, error) {
+	cfg, err := l.fetchJourneyConfig(ctx, journeyID)
+	if err == nil {
+		l.auditConfigChange(ctx, journeyID, cfg)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err != nil {
+		good, ok := l.lastGood[journeyID]
+		if !ok {
+			return nil, err
+		}
+
+		l.logger.Warn("journey config reload failed, serving last known good",
+			"journey_id", journeyID,
+			"error", err,
+		)
+		l.emitReloadFailed(journeyID)
+		l.cache[journeyID] = cachedConfig{cfg: good, expiresAt: l.now().Add(l.cacheTTL)}
+		return good, nil
+	}
+
+	l.cache[journeyID] = cachedConfig{cfg: cfg, expiresAt: l.now().Add(l.cacheTTL)}
+	l.lastGood[journeyID] = cfg
+	l.logger.Debug("loaded journey config", "journey_id", journeyID)
+
+	return cfg, nil
+}
+
+// emitReloadFailed counts a reload that fell back to the last known good
+// configuration, when a metrics emitter is configured.
+func (l *Loader) emitReloadFailed(journeyID string) {
+	if l.metrics == nil {
+		return
+	}
+
+	err := l.metrics.Emit(map[string]string{"JourneyID": journeyID}, []metrics.Metric{
+		{Name: "ConfigReloadFailed", Unit: metrics.UnitCount, Value: 1},
+	})
+	if err != nil {
+		l.logger.Warn("failed to emit config reload metric", "journey_id", journeyID, "error", err)
 	}
+}
 
+// fetchJourneyConfig fetches, parses and validates a journey configuration.
+func (l *Loader) fetchJourneyConfig(ctx context.Context, journeyID string) (*config.JourneyConfig, error) {
 	configName := fmt.Sprintf("journey.%s", journeyID)
-	data, err := l.loadProfile(configName)
+	data, err := l.loadProfile(ctx, configName)
 	if err != nil {
 		return nil, fmt.Errorf("load journey config %s: %w", journeyID, err)
 	}
 
+	if err := checkSchemaVersion(configName, data); err != nil {
+		return nil, err
+	}
+
 	var cfg config.JourneyConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse journey config %s: %w", journeyID, err)
+	if l.inheritDefaults {
+		settings, err := l.loadDefaultSettings(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load default journey config: %w", err)
+		}
+		cfg.Settings = settings
+	}
+
+	// Decoding over the inherited settings keeps defaults for fields the
+	// journey does not set; lists are replaced rather than merged.
+	if err := parseYAML(configName, data, &cfg); err != nil {
+		return nil, err
 	}
 
 	if err := config.ValidateJourneyConfig(&cfg); err != nil {
 		return nil, err
 	}
 
-	l.cache[journeyID] = &cfg
-	l.logger.Debug("loaded journey config", "journey_id", journeyID)
+	if err := l.checkAttemptTemplates(ctx, configName, &cfg); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
+// checkAttemptTemplates verifies that every templates_by_attempt target
+// exists, so a typo fails the load instead of the send. Actions with a
+// fallback_template are skipped, since the fallback covers a primary that is
+// not deployed yet. Templates that cannot be fetched are only logged, so a
+// template outage does not block the journey.
+func (l *Loader) checkAttemptTemplates(ctx context.Context, configName string, cfg *config.JourneyConfig) error {
+	if l.templates == nil {
+		return nil
+	}
+
+	var errs []error
+	check := func(path string, action config.Action) {
+		if action.FallbackTemplate != "" {
+			return
+		}
+		for attempt, ref := range action.TemplatesByAttempt {
+			_, err := l.templates.LoadTemplate(ctx, ref)
+			switch {
+			case err == nil:
+			case errors.Is(err, domain.ErrNotFound):
+				errs = append(errs, fmt.Errorf("%s.action.templates_by_attempt[%d]: %w", path, attempt, err))
+			default:
+				l.logger.Warn("could not check attempt template",
+					"config_name", configName,
+					"template", ref,
+					"error", err,
+				)
+			}
+		}
+	}
+
+	for i, repique := range cfg.Settings.LifecycleRepiques {
+		check(fmt.Sprintf("settings.lifecycle_repiques[%d]", i), repique.Action)
+	}
+	for i, step := range cfg.Steps {
+		for j, repique := range step.Repiques {
+			check(fmt.Sprintf("steps[%d].repiques[%d]", i, j), repique.Action)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &domain.ConfigError{
+			ConfigName: configName,
+			Field:      "templates_by_attempt",
+			Err:        fmt.Errorf("%w: %w", domain.ErrInvalidConfig, errors.Join(errs...)),
+		}
+	}
+
+	return nil
+}
+
+// checkSchemaVersion rejects journey configs written for a format this worker
+// does not understand, before they are misparsed into the current shape.
+func checkSchemaVersion(configName string, data []byte) error {
+	var header struct {
+		SchemaVersion int `yaml:"schema_version"`
+	}
+	if err := parseYAML(configName, data, &header); err != nil {
+		return err
+	}
+
+	switch header.SchemaVersion {
+	case 0, config.CurrentJourneySchemaVersion:
+		return nil
+	default:
+		return &domain.ConfigError{
+			ConfigName: configName,
+			Field:      "schema_version",
+			Err: fmt.Errorf("%w: unsupported version %d (supported: %d)",
+				domain.ErrInvalidConfig, header.SchemaVersion, config.CurrentJourneySchemaVersion),
+		}
+	}
+}
+
+// loadDefaultSettings loads the settings section of journey.default.
+func (l *Loader) loadDefaultSettings(ctx context.Context) (config.Settings, error) {
+	data, err := l.loadProfile(ctx, defaultJourneyProfile)
+	if err != nil {
+		return config.Settings{}, err
+	}
+
+	var defaults struct {
+		Settings config.Settings `yaml:"settings"`
+	}
+	if err := parseYAML(defaultJourneyProfile, data, &defaults); err != nil {
+		return config.Settings{}, err
+	}
+
+	return defaults.Settings, nil
+}
+
 // loadProfile fetches a configuration profile from AppConfig.
-func (l *Loader) loadProfile(profile string) ([]byte, error) {
+func (l *Loader) loadProfile(ctx context.Context, profile string) ([]byte, error) {
 	url := fmt.Sprintf("%s/%s.yaml", l.endpoint, profile)
 
-	resp, err := l.httpClient.Get(url)
+	data, status, err := l.fetch.get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("fetch config: %w", err)
 	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("config not found: %s (status %d)", profile, status)
+	}
+
+	return data, nil
+}
+
+// Ping checks that the AppConfig endpoint is reachable. Any response below
+// 500 counts as reachable, since the endpoint root need not be a profile.
+func (l *Loader) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := l.fetch.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reach appconfig: %w", err)
+	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
 			l.logger.Warn("failed to close response body", "error", closeErr)
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("config not found: %s (status %d)", profile, resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("reach appconfig: status %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return nil
 }
 
 // ClearCache clears the configuration cache.
+// Last-known-good configurations are kept so a failed reload can fall back to them.
 func (l *Loader) ClearCache() {
-	l.cache = make(map[string]*config.JourneyConfig)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache = make(map[string]cachedConfig)
 }
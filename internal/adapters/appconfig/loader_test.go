@@ -0,0 +1,235 @@
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+	"worker-project/internal/metrics"
+)
+
+const (
+	goodJourneyConfig = `
+journey:
+  id: checkout
+settings:
+  max_inactive_time:
+    minutes: 30
+`
+	invalidJourneyConfig = `
+journey:
+  id: checkout
+settings:
+  max_inactive_time:
+    minutes: 0
+`
+)
+
+func TestLoaderServesLastKnownGoodWhenReloadFails(t *testing.T) {
+	var body atomic.Value
+	body.Store(goodJourneyConfig)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/journey.checkout.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, body.Load().(string))
+	}))
+	defer server.Close()
+
+	var metricsOut bytes.Buffer
+	loader := NewLoader(config.AppConfigSettings{
+		Endpoint:     server.URL,
+		FetchTimeout: time.Second,
+		CacheTTL:     time.Minute,
+	}, nil, nil, metrics.NewEMFEmitter(&metricsOut, "Test"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	loader.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	first, err := loader.LoadJourneyConfig(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("initial load: %v", err)
+	}
+
+	body.Store(invalidJourneyConfig)
+
+	cached, err := loader.LoadJourneyConfig(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("cached load: %v", err)
+	}
+	if cached != first {
+		t.Fatal("config was refetched before the cache TTL expired")
+	}
+	if metricsOut.Len() != 0 {
+		t.Fatalf("unexpected metrics before reload: %s", metricsOut.String())
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	reloaded, err := loader.LoadJourneyConfig(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("reload with invalid config: %v", err)
+	}
+	if reloaded != first {
+		t.Fatal("reload did not fall back to the last known good config")
+	}
+	if !strings.Contains(metricsOut.String(), `"ConfigReloadFailed":1`) {
+		t.Fatalf("ConfigReloadFailed metric not emitted, got: %s", metricsOut.String())
+	}
+}
+
+func TestLoaderFailsWithoutLastKnownGood(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, invalidJourneyConfig)
+	}))
+	defer server.Close()
+
+	loader := NewLoader(config.AppConfigSettings{
+		Endpoint:     server.URL,
+		FetchTimeout: time.Second,
+		CacheTTL:     time.Minute,
+	}, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := loader.LoadJourneyConfig(context.Background(), "checkout"); err == nil {
+		t.Fatal("expected an error for an invalid config with no last known good")
+	}
+}
+
+// memoryHashStore is an in-memory ports.ConfigHashStore.
+type memoryHashStore map[string]string
+
+func (s memoryHashStore) SwapConfigHash(_ context.Context, journeyID, hash string) (string, bool, error) {
+	old, ok := s[journeyID]
+	s[journeyID] = hash
+	return old, ok, nil
+}
+
+func TestLoaderAuditsChangesAcrossLoaders(t *testing.T) {
+	var body atomic.Value
+	body.Store(goodJourneyConfig)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body.Load().(string))
+	}))
+	defer server.Close()
+
+	hashes := memoryHashStore{}
+	load := func() string {
+		var logs bytes.Buffer
+		loader := NewLoader(config.AppConfigSettings{
+			Endpoint:     server.URL,
+			FetchTimeout: time.Second,
+			CacheTTL:     time.Minute,
+		}, nil, hashes, nil, slog.New(slog.NewTextHandler(&logs, nil)))
+		if _, err := loader.LoadJourneyConfig(context.Background(), "checkout"); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		return logs.String()
+	}
+
+	if logs := load(); !strings.Contains(logs, "event=first_load") {
+		t.Fatalf("first load not audited, got: %s", logs)
+	}
+	if logs := load(); strings.Contains(logs, "journey config audit") {
+		t.Fatalf("unchanged config audited on a new loader, got: %s", logs)
+	}
+
+	body.Store(goodJourneyConfig + "  burn_in:\n    minutes: 10\n")
+	if logs := load(); !strings.Contains(logs, "event=changed") {
+		t.Fatalf("config change not audited, got: %s", logs)
+	}
+}
+
+func TestLoaderChecksAttemptTemplates(t *testing.T) {
+	const templates = `
+templates:
+  soft:
+    channel: whatsapp
+    content: {type: text, body: "Still there?"}
+  firm:
+    channel: whatsapp
+    content: {type: text, body: "Your cart expires soon"}
+`
+	journey := func(templateConfig, secondTemplate, fallback string) string {
+		return goodJourneyConfig + `
+steps:
+  - id: cart
+    repiques:
+      - id: nudge
+        max_attempts: 2
+        action:
+          template: ` + templateConfig + `:not_deployed_yet
+          fallback_template: ` + fallback + `
+          templates_by_attempt:
+            1: ` + templateConfig + `:soft
+            2: ` + templateConfig + `:` + secondTemplate + `
+`
+	}
+
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/journey.checkout.yaml":
+			_, _ = io.WriteString(w, body.Load().(string))
+		case "/journey.checkout.templates.yaml":
+			_, _ = io.WriteString(w, templates)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		templateConfig string
+		secondTemplate string
+		fallback       string
+		wantErr        bool
+	}{
+		{name: "known templates", templateConfig: "journey.checkout.templates", secondTemplate: "firm"},
+		{name: "unknown attempt template", templateConfig: "journey.checkout.templates", secondTemplate: "frim", wantErr: true},
+		{name: "unknown template covered by fallback", templateConfig: "journey.checkout.templates", secondTemplate: "frim", fallback: "journey.checkout.templates:soft"},
+		{name: "template config unavailable", templateConfig: "journey.missing.templates", secondTemplate: "firm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body.Store(journey(tt.templateConfig, tt.secondTemplate, tt.fallback))
+
+			settings := config.AppConfigSettings{
+				Endpoint:         server.URL,
+				FetchTimeout:     time.Second,
+				CacheTTL:         time.Minute,
+				RenderTimeout:    time.Second,
+				MaxRenderedBytes: 1024,
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			loader := NewLoader(settings, NewTemplateRenderer(settings, logger), nil, nil, logger)
+
+			_, err := loader.LoadJourneyConfig(context.Background(), "checkout")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("load: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrInvalidConfig) {
+				t.Fatalf("expected ErrInvalidConfig, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), "frim") {
+				t.Fatalf("error does not name the unknown template: %v", err)
+			}
+		})
+	}
+}
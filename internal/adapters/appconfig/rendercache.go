@@ -0,0 +1,84 @@
+package appconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// maxRenderCacheEntries bounds the render cache; renders past it are not cached.
+const maxRenderCacheEntries = 10000
+
+// renderCache reuses rendered bodies for customers whose metadata agrees on
+// every field a template reads. It is cleared with the renderer's template
+// cache at the start of each run.
+type renderCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{entries: make(map[string]string)}
+}
+
+func (c *renderCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, ok := c.entries[key]
+	return body, ok
+}
+
+func (c *renderCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]string)
+}
+
+func (c *renderCache) put(key, body string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) < maxRenderCacheEntries {
+		c.entries[key] = body
+	}
+}
+
+// renderKey hashes a template with the metadata values of the given fields.
+// Fields absent from metadata are distinguished from null values.
+func renderKey(ref, body string, fields []string, metadata map[string]any) (string, bool) {
+	values := make(map[string]any, len(fields))
+	for _, field := range fields {
+		value, present := lookupField(metadata, field)
+		values[field] = []any{present, value}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(ref))
+	h.Write([]byte{0})
+	h.Write([]byte(body))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// lookupField resolves a dotted field path in metadata. When the path runs
+// through a value other than a map, that whole value is returned.
+func lookupField(metadata map[string]any, field string) (any, bool) {
+	var current any = metadata
+	for _, key := range strings.Split(field, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return current, true
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,131 @@
+package appconfig
+
+import (
+	"slices"
+	"testing"
+	"text/template"
+
+	"worker-project/internal/config"
+)
+
+// renderCounter counts how often a template reads it. It has no exported
+// fields, so it does not change render keys.
+type renderCounter struct {
+	calls int
+}
+
+func (c *renderCounter) Count() string {
+	c.calls++
+	return ""
+}
+
+func TestRenderCacheReusesRenders(t *testing.T) {
+	r := newTestRenderer(config.AppConfigSettings{RenderCache: true})
+	tmpl := textTemplate("Hi {{.name}}{{.counter.Count}}, your {{.cart.item}} is waiting")
+	counter := &renderCounter{}
+
+	customer := func(number, name string) map[string]any {
+		return map[string]any{
+			"customer_number": number, // not read by the template
+			"name":            name,
+			"cart":            map[string]any{"item": "shoes", "size": number},
+			"counter":         counter,
+		}
+	}
+
+	first, err := r.Render(tmpl, customer("5511999990001", "Ana"))
+	if err != nil {
+		t.Fatalf("first render: %v", err)
+	}
+	second, err := r.Render(tmpl, customer("5511999990002", "Ana"))
+	if err != nil {
+		t.Fatalf("second render: %v", err)
+	}
+	if counter.calls != 1 {
+		t.Fatalf("template executed %d times for matching metadata, want 1", counter.calls)
+	}
+	if first != second || first != "Hi Ana, your shoes is waiting" {
+		t.Fatalf("renders = %q and %q", first, second)
+	}
+
+	if _, err := r.Render(tmpl, customer("5511999990003", "Bia")); err != nil {
+		t.Fatalf("third render: %v", err)
+	}
+	if counter.calls != 2 {
+		t.Fatalf("template executed %d times after a relevant field changed, want 2", counter.calls)
+	}
+
+	r.ClearCache()
+	if _, err := r.Render(tmpl, customer("5511999990001", "Ana")); err != nil {
+		t.Fatalf("render after clear: %v", err)
+	}
+	if counter.calls != 3 {
+		t.Fatalf("template executed %d times after ClearCache, want 3", counter.calls)
+	}
+}
+
+func TestRenderCacheSkipsTemplatesWithUnknownFields(t *testing.T) {
+	r := newTestRenderer(config.AppConfigSettings{RenderCache: true})
+	tmpl := textTemplate("{{.counter.Count}}{{range .items}}{{.}} {{end}}")
+	counter := &renderCounter{}
+
+	for _, items := range [][]string{{"shoes"}, {"shoes"}} {
+		if _, err := r.Render(tmpl, map[string]any{"items": items, "counter": counter}); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+	}
+	if counter.calls != 2 {
+		t.Fatalf("template executed %d times, want 2 (not cacheable)", counter.calls)
+	}
+}
+
+func TestRenderKeyFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   []string
+		wantOK bool
+	}{
+		{name: "fields", body: "Hi {{.name}}, {{currency .cart.total}}", want: []string{"cart.total", "name"}, wantOK: true},
+		{name: "conditions", body: "{{if .vip}}Hi {{.name}}{{else}}Hello{{end}}", want: []string{"name", "vip"}, wantOK: true},
+		{name: "variables", body: "{{$cart := .cart}}{{$cart.total}} {{$.name}}", want: []string{"cart", "name"}, wantOK: true},
+		{name: "no fields", body: "Hello!", want: []string{}, wantOK: true},
+		{name: "whole data", body: "{{.}}"},
+		{name: "range", body: "{{range .items}}{{.}}{{end}}"},
+		{name: "with", body: "{{with .cart}}{{.total}}{{end}}"},
+		{name: "template call", body: `{{define "greeting"}}Hi{{end}}{{template "greeting" .}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := template.New("message").Funcs(templateFuncs()).Parse(tt.body)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			fields, ok := renderKeyFields(parsed)
+			if ok != tt.wantOK {
+				t.Fatalf("renderKeyFields ok = %v, want %v (fields %v)", ok, tt.wantOK, fields)
+			}
+			if ok && !slices.Equal(fields, tt.want) {
+				t.Errorf("fields = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderKeyDistinguishesMissingFromNull(t *testing.T) {
+	fields := []string{"name"}
+
+	missing, ok := renderKey("ref", "body", fields, map[string]any{})
+	if !ok {
+		t.Fatal("renderKey failed for missing field")
+	}
+	null, ok := renderKey("ref", "body", fields, map[string]any{"name": nil})
+	if !ok {
+		t.Fatal("renderKey failed for null field")
+	}
+	if missing == null {
+		t.Error("missing and null fields share a render key")
+	}
+}
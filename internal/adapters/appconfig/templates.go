@@ -2,16 +2,17 @@ package appconfig
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 	"text/template"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"worker-project/internal/config"
+	"worker-project/internal/domain"
 	"worker-project/internal/ports"
 )
 
@@ -22,8 +23,10 @@ type TemplateConfig struct {
 
 // TemplateDefinition represents a single template definition.
 type TemplateDefinition struct {
-	Channel string                 `yaml:"channel"`
-	Content TemplateContentDef     `yaml:"content"`
+	Channel  string             `yaml:"channel"`
+	Category string             `yaml:"category,omitempty"`
+	Content  TemplateContentDef `yaml:"content"`
+	Split    SplitDef           `yaml:"split,omitempty"`
 }
 
 // TemplateContentDef holds the content type and body.
@@ -32,101 +35,204 @@ type TemplateContentDef struct {
 	Body string `yaml:"body"`
 }
 
+// SplitDef controls splitting of bodies that exceed the channel's length limit.
+type SplitDef struct {
+	Enabled  bool `yaml:"enabled"`
+	MaxParts int  `yaml:"max_parts"`
+	Numbered bool `yaml:"numbered"`
+}
+
 // TemplateRenderer implements ports.TemplateRenderer using AppConfig.
 type TemplateRenderer struct {
-	httpClient *http.Client
-	endpoint   string
-	logger     *slog.Logger
-	cache      map[string]*TemplateConfig
+	fetch            fetcher
+	endpoint         string
+	renderTimeout    time.Duration
+	maxRenderedBytes int
+	renders          *renderCache // nil when the render cache is disabled
+	logger           *slog.Logger
+
+	mu     sync.RWMutex
+	cache  map[string]*TemplateConfig
+	flight flightGroup[*TemplateConfig]
 }
 
 // NewTemplateRenderer creates a new template renderer.
 func NewTemplateRenderer(cfg config.AppConfigSettings, logger *slog.Logger) *TemplateRenderer {
-	return &TemplateRenderer{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		endpoint: cfg.Endpoint,
-		logger:   logger,
-		cache:    make(map[string]*TemplateConfig),
+	r := &TemplateRenderer{
+		fetch:            newFetcher(cfg, logger),
+		endpoint:         cfg.Endpoint,
+		renderTimeout:    cfg.RenderTimeout,
+		maxRenderedBytes: cfg.MaxRenderedBytes,
+		logger:           logger,
+		cache:            make(map[string]*TemplateConfig),
+	}
+	if cfg.RenderCache {
+		r.renders = newRenderCache()
 	}
+	return r
 }
 
 // LoadTemplate loads a template by reference.
 // Format: "config_name:template_key" (e.g., "journey.account_creation.templates:reminder_10_min")
-func (r *TemplateRenderer) LoadTemplate(templateRef string) (*ports.Template, error) {
+func (r *TemplateRenderer) LoadTemplate(ctx context.Context, templateRef string) (*ports.Template, error) {
 	configName, templateKey, err := parseTemplateRef(templateRef)
 	if err != nil {
 		return nil, err
 	}
 
-	templateConfig, err := r.loadTemplateConfig(configName)
+	templateConfig, err := r.loadTemplateConfig(ctx, configName)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateConfig.lookup(templateRef, configName, templateKey)
+}
+
+// LoadCandidateTemplate loads a template by reference from candidate
+// template config YAML instead of AppConfig, e.g. to preview a change
+// before it is deployed.
+func LoadCandidateTemplate(templateRef string, data []byte) (*ports.Template, error) {
+	configName, templateKey, err := parseTemplateRef(templateRef)
 	if err != nil {
 		return nil, err
 	}
 
-	def, ok := templateConfig.Templates[templateKey]
+	var cfg TemplateConfig
+	if err := parseYAML(configName, data, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.lookup(templateRef, configName, templateKey)
+}
+
+// lookup returns the template stored under templateKey.
+func (c *TemplateConfig) lookup(templateRef, configName, templateKey string) (*ports.Template, error) {
+	def, ok := c.Templates[templateKey]
 	if !ok {
-		return nil, fmt.Errorf("template key %s not found in config %s", templateKey, configName)
+		return nil, fmt.Errorf("template key %s in config %s: %w", templateKey, configName, domain.ErrNotFound)
 	}
 
 	return &ports.Template{
-		Channel: def.Channel,
+		Ref:      templateRef,
+		Channel:  def.Channel,
+		Category: def.Category,
 		Content: ports.TemplateContent{
 			Type: def.Content.Type,
 			Body: def.Content.Body,
 		},
+		Split: ports.SplitOptions{
+			Enabled:  def.Split.Enabled,
+			MaxParts: def.Split.MaxParts,
+			Numbered: def.Split.Numbered,
+		},
 	}, nil
 }
 
 // Render applies metadata to a template and returns the rendered content.
+// Execution is bounded by the render timeout and the output by the max
+// rendered size. A timed-out execution cannot be interrupted and finishes in
+// the background, but its output stays capped. With the render cache
+// enabled, a render is reused for metadata that matches on every field the
+// template reads.
 func (r *TemplateRenderer) Render(tmpl *ports.Template, metadata map[string]any) (string, error) {
-	t, err := template.New("message").Parse(tmpl.Content.Body)
+	t, err := template.New("message").Funcs(templateFuncs()).Parse(tmpl.Content.Body)
 	if err != nil {
 		return "", fmt.Errorf("parse template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, metadata); err != nil {
-		return "", fmt.Errorf("execute template: %w", err)
+	// Missing fields render as "<no value>" rather than failing, so report
+	// them to trace gaps in upstream data.
+	for _, field := range missingFields(templateFields(t), metadata) {
+		r.logger.Warn("template field missing from metadata", "template", tmpl.Ref, "field", field)
 	}
 
-	return buf.String(), nil
+	var cacheKey string
+	if r.renders != nil {
+		if fields, ok := renderKeyFields(t); ok {
+			if key, ok := renderKey(tmpl.Ref, tmpl.Content.Body, fields, metadata); ok {
+				if body, hit := r.renders.get(key); hit {
+					return body, nil
+				}
+				cacheKey = key
+			}
+		}
+	}
+
+	buf := &limitedBuffer{limit: r.maxRenderedBytes}
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Execute(buf, metadata)
+	}()
+
+	timer := time.NewTimer(r.renderTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			if errors.Is(err, domain.ErrRenderTooLarge) {
+				return "", fmt.Errorf("execute template: %w (limit %d bytes)", domain.ErrRenderTooLarge, r.maxRenderedBytes)
+			}
+			return "", fmt.Errorf("execute template: %w", err)
+		}
+		if cacheKey != "" {
+			r.renders.put(cacheKey, buf.String())
+		}
+		return buf.String(), nil
+	case <-timer.C:
+		return "", fmt.Errorf("execute template: %w after %s", domain.ErrRenderTimeout, r.renderTimeout)
+	}
+}
+
+// limitedBuffer is a bytes.Buffer that rejects writes past a size limit.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
 }
 
-// loadTemplateConfig fetches and caches a template configuration.
-func (r *TemplateRenderer) loadTemplateConfig(configName string) (*TemplateConfig, error) {
-	if cached, ok := r.cache[configName]; ok {
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, domain.ErrRenderTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// loadTemplateConfig returns a cached template configuration, fetching it
+// when missing. Concurrent loads of the same config share a single fetch.
+func (r *TemplateRenderer) loadTemplateConfig(ctx context.Context, configName string) (*TemplateConfig, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[configName]
+	r.mu.RUnlock()
+	if ok {
 		return cached, nil
 	}
 
+	return r.flight.Do(ctx, configName, func() (*TemplateConfig, error) {
+		return r.fetchTemplateConfig(ctx, configName)
+	})
+}
+
+// fetchTemplateConfig fetches a template configuration and caches it.
+func (r *TemplateRenderer) fetchTemplateConfig(ctx context.Context, configName string) (*TemplateConfig, error) {
 	url := fmt.Sprintf("%s/%s.yaml", r.endpoint, configName)
 
-	resp, err := r.httpClient.Get(url)
+	data, status, err := r.fetch.get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("fetch template config: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			r.logger.Warn("failed to close response body", "error", closeErr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("template config not found: %s (status %d)", configName, resp.StatusCode)
-	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read template config: %w", err)
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("template config not found: %s (status %d)", configName, status)
 	}
 
 	var cfg TemplateConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse template config: %w", err)
+	if err := parseYAML(configName, data, &cfg); err != nil {
+		return nil, err
 	}
 
+	r.mu.Lock()
 	r.cache[configName] = &cfg
+	r.mu.Unlock()
 	r.logger.Debug("loaded template config", "config_name", configName)
 
 	return &cfg, nil
@@ -142,7 +248,12 @@ func parseTemplateRef(ref string) (configName, templateKey string, err error) {
 	return "", "", fmt.Errorf("invalid template reference format: %s (expected 'config_name:template_key')", ref)
 }
 
-// ClearCache clears the template configuration cache.
+// ClearCache clears the template configuration cache and the render cache.
 func (r *TemplateRenderer) ClearCache() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.cache = make(map[string]*TemplateConfig)
+	if r.renders != nil {
+		r.renders.clear()
+	}
 }
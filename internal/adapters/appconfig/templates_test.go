@@ -0,0 +1,93 @@
+package appconfig
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+	"worker-project/internal/ports"
+)
+
+// slowValue is metadata whose Value method blocks, to hold up a render.
+type slowValue struct {
+	delay time.Duration
+}
+
+func (v slowValue) Value() string {
+	time.Sleep(v.delay)
+	return "late"
+}
+
+func newTestRenderer(settings config.AppConfigSettings) *TemplateRenderer {
+	if settings.RenderTimeout == 0 {
+		settings.RenderTimeout = time.Second
+	}
+	if settings.MaxRenderedBytes == 0 {
+		settings.MaxRenderedBytes = 1024
+	}
+	return NewTemplateRenderer(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func textTemplate(body string) *ports.Template {
+	return &ports.Template{
+		Ref:     "journey.checkout.templates:reminder",
+		Channel: "whatsapp",
+		Content: ports.TemplateContent{Type: "text", Body: body},
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := newTestRenderer(config.AppConfigSettings{})
+
+	got, err := r.Render(textTemplate("Hi {{.name}}, your total is {{currency .total}}"), map[string]any{
+		"name":  "Ana",
+		"total": 1234.5,
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "Hi Ana, your total is R$ 1.234,50"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTimeout(t *testing.T) {
+	r := newTestRenderer(config.AppConfigSettings{RenderTimeout: 10 * time.Millisecond})
+
+	start := time.Now()
+	_, err := r.Render(textTemplate("Hi {{.slow.Value}}"), map[string]any{
+		"slow": slowValue{delay: 500 * time.Millisecond},
+	})
+	if !errors.Is(err, domain.ErrRenderTimeout) {
+		t.Fatalf("expected ErrRenderTimeout, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("Render returned after %s, want about the 10ms timeout", elapsed)
+	}
+}
+
+func TestRenderOutputLimit(t *testing.T) {
+	r := newTestRenderer(config.AppConfigSettings{MaxRenderedBytes: 64})
+	tmpl := textTemplate("{{range .items}}{{.}}, {{end}}")
+
+	if _, err := r.Render(tmpl, map[string]any{"items": []string{"shoes", "socks"}}); err != nil {
+		t.Fatalf("Render within limit: %v", err)
+	}
+
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = "shoes"
+	}
+	_, err := r.Render(tmpl, map[string]any{"items": items})
+	if !errors.Is(err, domain.ErrRenderTooLarge) {
+		t.Fatalf("expected ErrRenderTooLarge, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "limit 64 bytes") {
+		t.Errorf("error does not report the limit: %v", err)
+	}
+}
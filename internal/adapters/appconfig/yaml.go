@@ -0,0 +1,58 @@
+package appconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"worker-project/internal/domain"
+)
+
+// snippetContext is the number of lines shown around an offending YAML line.
+const snippetContext = 2
+
+// yamlLinePattern extracts the line number yaml.v3 embeds in its error messages.
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// parseYAML decodes a configuration document. Parse errors are returned as a
+// domain.ConfigError naming the config and, when the yaml library reports
+// it, the offending line with the surrounding region of the document.
+func parseYAML(configName string, data []byte, out any) error {
+	err := yaml.Unmarshal(data, out)
+	if err == nil {
+		return nil
+	}
+
+	configErr := &domain.ConfigError{ConfigName: configName, Err: err}
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		if line, convErr := strconv.Atoi(m[1]); convErr == nil {
+			configErr.Line = line
+			configErr.Snippet = yamlSnippet(data, line)
+		}
+	}
+	return configErr
+}
+
+// yamlSnippet returns the lines around a 1-based line number, marking it.
+func yamlSnippet(data []byte, line int) string {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first := max(line-snippetContext, 1)
+	last := min(line+snippetContext, len(lines))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %4d | %s\n", marker, n, lines[n-1])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
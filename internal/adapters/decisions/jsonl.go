@@ -0,0 +1,94 @@
+package decisions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"worker-project/internal/domain"
+)
+
+// StdoutPath selects standard output as the decision log destination.
+const StdoutPath = "-"
+
+// record is the JSONL form of a decision. Customer numbers are replaced by a
+// salted hash so the dataset can be shared without exposing them.
+type record struct {
+	EvaluatedAt       string  `json:"evaluated_at"`
+	JourneyID         string  `json:"journey_id"`
+	CustomerHash      string  `json:"customer_hash"`
+	Step              string  `json:"step,omitempty"`
+	RepiqueID         string  `json:"repique_id"`
+	Attempt           int     `json:"attempt"`
+	Triggered         bool    `json:"triggered"`
+	Reason            string  `json:"reason"`
+	InactivitySeconds float64 `json:"inactivity_seconds"`
+}
+
+// JSONLLog implements ports.DecisionLog by appending one JSON object per
+// decision to a file or standard output.
+type JSONLLog struct {
+	mu     sync.Mutex
+	output io.Writer
+	closer io.Closer
+	salt   string
+}
+
+// NewJSONLLog opens the decision log at path, appending to an existing file.
+// StdoutPath writes to standard output, where Lambda forwards it to
+// CloudWatch Logs.
+func NewJSONLLog(path, salt string) (*JSONLLog, error) {
+	if path == StdoutPath {
+		return &JSONLLog{output: os.Stdout, salt: salt}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open decision log: %w", err)
+	}
+	return &JSONLLog{output: f, closer: f, salt: salt}, nil
+}
+
+// Record appends a decision to the log.
+func (l *JSONLLog) Record(_ context.Context, decision domain.Decision) error {
+	data, err := json.Marshal(record{
+		EvaluatedAt:       decision.EvaluatedAt.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		JourneyID:         decision.JourneyID,
+		CustomerHash:      l.hash(decision.CustomerNumber),
+		Step:              decision.Step,
+		RepiqueID:         decision.RepiqueID,
+		Attempt:           decision.Attempt,
+		Triggered:         decision.Triggered,
+		Reason:            decision.Reason,
+		InactivitySeconds: decision.Inactivity.Seconds(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal decision: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.output.Write(data); err != nil {
+		return fmt.Errorf("write decision: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file. It is a no-op for standard output.
+func (l *JSONLLog) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+func (l *JSONLLog) hash(customerNumber string) string {
+	sum := sha256.Sum256([]byte(l.salt + customerNumber))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,15 @@
+package decisions
+
+import (
+	"context"
+
+	"worker-project/internal/domain"
+)
+
+// NoopLog implements ports.DecisionLog by discarding every decision.
+type NoopLog struct{}
+
+// Record discards the decision.
+func (NoopLog) Record(context.Context, domain.Decision) error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package events
+
+import (
+	"context"
+
+	"worker-project/internal/domain"
+)
+
+// NoopPublisher implements ports.EventPublisher by discarding every event.
+type NoopPublisher struct{}
+
+// Publish discards the event.
+func (NoopPublisher) Publish(context.Context, domain.LifecycleEvent) error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+)
+
+// HTTPEvaluator implements ports.FeatureFlagEvaluator against an HTTP flag service.
+// It requests GET {endpoint}/flags/{flag}?customer_number=...&tenant_id=...&journey_id=...
+// and expects a JSON body of the form {"enabled": true}.
+// Evaluations are cached per flag and customer for a short period.
+type HTTPEvaluator struct {
+	httpClient *http.Client
+	endpoint   string
+	cacheTTL   time.Duration
+	logger     *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedFlag
+}
+
+type cachedFlag struct {
+	enabled   bool
+	expiresAt time.Time
+}
+
+type flagResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// NewHTTPEvaluator creates a new HTTP-backed feature flag evaluator.
+func NewHTTPEvaluator(cfg config.FeatureFlagSettings, logger *slog.Logger) *HTTPEvaluator {
+	return &HTTPEvaluator{
+		httpClient: &http.Client{
+			Timeout: 2 * time.Second,
+		},
+		endpoint: cfg.Endpoint,
+		cacheTTL: cfg.CacheTTL,
+		logger:   logger,
+		cache:    make(map[string]cachedFlag),
+	}
+}
+
+// IsEnabled reports whether a flag is enabled for the customer of a journey state.
+func (e *HTTPEvaluator) IsEnabled(ctx context.Context, flag string, state *domain.JourneyState) (bool, error) {
+	cacheKey := flag + "|" + state.TenantID + "|" + state.CustomerNumber
+
+	e.mu.Lock()
+	cached, ok := e.cache[cacheKey]
+	e.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.enabled, nil
+	}
+
+	enabled, err := e.fetch(ctx, flag, state)
+	if err != nil {
+		return false, err
+	}
+
+	e.mu.Lock()
+	e.cache[cacheKey] = cachedFlag{enabled: enabled, expiresAt: time.Now().Add(e.cacheTTL)}
+	e.mu.Unlock()
+
+	return enabled, nil
+}
+
+func (e *HTTPEvaluator) fetch(ctx context.Context, flag string, state *domain.JourneyState) (bool, error) {
+	query := url.Values{}
+	query.Set("customer_number", state.CustomerNumber)
+	query.Set("tenant_id", state.TenantID)
+	query.Set("journey_id", state.JourneyID)
+	reqURL := fmt.Sprintf("%s/flags/%s?%s", e.endpoint, url.PathEscape(flag), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("build flag request: %w", err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("fetch flag %s: %w", flag, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			e.logger.Warn("failed to close response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("fetch flag %s: status %d", flag, resp.StatusCode)
+	}
+
+	var body flagResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("decode flag %s: %w", flag, err)
+	}
+
+	return body.Enabled, nil
+}
@@ -3,6 +3,7 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 
 	"worker-project/internal/domain"
@@ -13,13 +14,27 @@ import (
 // This is a stub implementation that logs messages instead of sending them.
 type Client struct {
 	templateRenderer ports.TemplateRenderer
+	shortener        ports.URLShortener
+	spend            *SpendLimiter
+	signer           *Signer
 	logger           *slog.Logger
 }
 
-// NewClient creates a new messaging client.
-func NewClient(templateRenderer ports.TemplateRenderer, logger *slog.Logger) *Client {
+// NewClient creates a new messaging client. The shortener, spend limiter and
+// signer are optional; when nil, links are sent unchanged, spend is not capped
+// and messages are not signed.
+func NewClient(
+	templateRenderer ports.TemplateRenderer,
+	shortener ports.URLShortener,
+	spend *SpendLimiter,
+	signer *Signer,
+	logger *slog.Logger,
+) *Client {
 	return &Client{
 		templateRenderer: templateRenderer,
+		shortener:        shortener,
+		spend:            spend,
+		signer:           signer,
 		logger:           logger,
 	}
 }
@@ -31,11 +46,22 @@ func NewClient(templateRenderer ports.TemplateRenderer, logger *slog.Logger) *Cl
 // - Send to SQS queue
 // - Call external notification API
 func (c *Client) Send(ctx context.Context, msg domain.Message) error {
-	template, err := c.templateRenderer.LoadTemplate(msg.Template)
+	templateRef := msg.Template
+	template, err := c.templateRenderer.LoadTemplate(ctx, templateRef)
+	if err != nil && msg.FallbackTemplate != "" {
+		c.logger.Warn("failed to load template, using fallback",
+			"customer_number", msg.CustomerNumber,
+			"template", msg.Template,
+			"fallback_template", msg.FallbackTemplate,
+			"error", err,
+		)
+		templateRef = msg.FallbackTemplate
+		template, err = c.templateRenderer.LoadTemplate(ctx, templateRef)
+	}
 	if err != nil {
 		return &domain.MessagingError{
 			CustomerNumber: msg.CustomerNumber,
-			TemplateRef:    msg.Template,
+			TemplateRef:    templateRef,
 			Err:            err,
 		}
 	}
@@ -44,57 +70,112 @@ func (c *Client) Send(ctx context.Context, msg domain.Message) error {
 	if err != nil {
 		return &domain.MessagingError{
 			CustomerNumber: msg.CustomerNumber,
-			TemplateRef:    msg.Template,
+			TemplateRef:    templateRef,
+			Err:            fmt.Errorf("%w: %w", domain.ErrRenderFailed, err),
+		}
+	}
+
+	renderedBody = c.shortenLinks(ctx, renderedBody)
+	if err := ctx.Err(); err != nil {
+		return &domain.MessagingError{
+			CustomerNumber: msg.CustomerNumber,
+			TemplateRef:    templateRef,
 			Err:            err,
 		}
 	}
 
-	finalMessage := map[string]any{
-		"customer_number": msg.CustomerNumber,
-		"tenant_id":       msg.TenantID,
-		"contact_id":      msg.ContactID,
-		"repique_id":      msg.RepiqueID,
-		"step":            msg.Step,
-		"channel":         template.Channel,
-		"content": map[string]any{
-			"type": template.Content.Type,
-			"body": renderedBody,
-		},
+	if c.signer != nil {
+		renderedBody = c.signer.sign(renderedBody, msg.TenantID, msg.CustomerNumber, msg.JourneyID)
 	}
 
-	data, err := json.MarshalIndent(finalMessage, "", "  ")
+	parts, err := splitBody(renderedBody, MaxBodyLength, template.Split)
 	if err != nil {
 		return &domain.MessagingError{
 			CustomerNumber: msg.CustomerNumber,
-			TemplateRef:    msg.Template,
-			Err:            err,
+			TemplateRef:    templateRef,
+			Err:            fmt.Errorf("%w: %w", domain.ErrRenderFailed, err),
+		}
+	}
+
+	var cost float64
+	if c.spend != nil {
+		cost, err = c.spend.check(ctx, template.Category, len(parts))
+		if err != nil {
+			return &domain.MessagingError{
+				CustomerNumber: msg.CustomerNumber,
+				TemplateRef:    templateRef,
+				Err:            err,
+			}
 		}
 	}
 
-	c.logger.Info("sending message",
-		"customer_number", msg.CustomerNumber,
-		"repique_id", msg.RepiqueID,
-		"channel", template.Channel,
-	)
-	c.logger.Debug("message payload", "payload", string(data))
-
-	// TODO: Implement actual message sending here
-	// Example implementations:
-	//
-	// SNS:
-	//   snsClient.Publish(ctx, &sns.PublishInput{
-	//       TopicArn: aws.String(topicArn),
-	//       Message:  aws.String(string(data)),
-	//   })
-	//
-	// SQS:
-	//   sqsClient.SendMessage(ctx, &sqs.SendMessageInput{
-	//       QueueUrl:    aws.String(queueUrl),
-	//       MessageBody: aws.String(string(data)),
-	//   })
-	//
-	// HTTP:
-	//   httpClient.Post(apiURL, "application/json", bytes.NewReader(data))
+	for i, part := range parts {
+		// Stop between parts on cancellation, charging only the parts sent.
+		if err := ctx.Err(); err != nil {
+			if c.spend != nil {
+				c.spend.record(context.WithoutCancel(ctx), cost*float64(i)/float64(len(parts)))
+			}
+			return &domain.MessagingError{
+				CustomerNumber: msg.CustomerNumber,
+				TemplateRef:    templateRef,
+				Err:            err,
+			}
+		}
+
+		finalMessage := map[string]any{
+			"customer_number": msg.CustomerNumber,
+			"tenant_id":       msg.TenantID,
+			"contact_id":      msg.ContactID,
+			"repique_id":      msg.RepiqueID,
+			"step":            msg.Step,
+			"channel":         template.Channel,
+			"content": map[string]any{
+				"type": template.Content.Type,
+				"body": part,
+			},
+		}
+
+		data, err := json.MarshalIndent(finalMessage, "", "  ")
+		if err != nil {
+			return &domain.MessagingError{
+				CustomerNumber: msg.CustomerNumber,
+				TemplateRef:    templateRef,
+				Err:            err,
+			}
+		}
+
+		c.logger.Info("sending message",
+			"customer_number", msg.CustomerNumber,
+			"repique_id", msg.RepiqueID,
+			"template", templateRef,
+			"channel", template.Channel,
+			"part", i+1,
+			"parts", len(parts),
+		)
+		c.logger.Debug("message payload", "payload", string(data))
+
+		// TODO: Implement actual message sending here
+		// Example implementations:
+		//
+		// SNS:
+		//   snsClient.Publish(ctx, &sns.PublishInput{
+		//       TopicArn: aws.String(topicArn),
+		//       Message:  aws.String(string(data)),
+		//   })
+		//
+		// SQS:
+		//   sqsClient.SendMessage(ctx, &sqs.SendMessageInput{
+		//       QueueUrl:    aws.String(queueUrl),
+		//       MessageBody: aws.String(string(data)),
+		//   })
+		//
+		// HTTP:
+		//   httpClient.Post(apiURL, "application/json", bytes.NewReader(data))
+	}
+
+	if c.spend != nil {
+		c.spend.record(ctx, cost)
+	}
 
 	return nil
 }
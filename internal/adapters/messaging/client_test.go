@@ -0,0 +1,88 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"worker-project/internal/domain"
+	"worker-project/internal/ports"
+)
+
+// stubRenderer serves a fixed set of templates and records what it renders.
+type stubRenderer struct {
+	templates map[string]*ports.Template
+	rendered  []string
+}
+
+func (r *stubRenderer) LoadTemplate(_ context.Context, templateRef string) (*ports.Template, error) {
+	tmpl, ok := r.templates[templateRef]
+	if !ok {
+		return nil, fmt.Errorf("template %s: %w", templateRef, domain.ErrNotFound)
+	}
+	return tmpl, nil
+}
+
+func (r *stubRenderer) Render(tmpl *ports.Template, _ map[string]any) (string, error) {
+	r.rendered = append(r.rendered, tmpl.Ref)
+	return tmpl.Content.Body, nil
+}
+
+func TestClientSendFallsBackToFallbackTemplate(t *testing.T) {
+	const (
+		primary  = "journey.checkout.templates:discount"
+		fallback = "journey.checkout.templates:reminder"
+	)
+	template := func(ref string) *ports.Template {
+		return &ports.Template{Ref: ref, Channel: "whatsapp", Content: ports.TemplateContent{Type: "text", Body: "Hi"}}
+	}
+
+	tests := []struct {
+		name         string
+		templates    []string
+		fallback     string
+		wantRendered []string
+		wantErr      bool
+	}{
+		{name: "primary found", templates: []string{primary, fallback}, fallback: fallback, wantRendered: []string{primary}},
+		{name: "primary missing, fallback used", templates: []string{fallback}, fallback: fallback, wantRendered: []string{fallback}},
+		{name: "primary missing without fallback", templates: []string{fallback}, wantErr: true},
+		{name: "both missing", fallback: fallback, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := &stubRenderer{templates: make(map[string]*ports.Template)}
+			for _, ref := range tt.templates {
+				renderer.templates[ref] = template(ref)
+			}
+			client := NewClient(renderer, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			err := client.Send(context.Background(), domain.Message{
+				CustomerNumber:   "5511999990000",
+				Template:         primary,
+				FallbackTemplate: tt.fallback,
+			})
+			if tt.wantErr {
+				var msgErr *domain.MessagingError
+				if !errors.As(err, &msgErr) || !errors.Is(err, domain.ErrNotFound) {
+					t.Fatalf("expected a MessagingError wrapping ErrNotFound, got: %v", err)
+				}
+				if len(renderer.rendered) != 0 {
+					t.Fatalf("rendered %v despite the error", renderer.rendered)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if !slices.Equal(renderer.rendered, tt.wantRendered) {
+				t.Errorf("rendered %v, want %v", renderer.rendered, tt.wantRendered)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package messaging
+
+import (
+	"context"
+	"regexp"
+)
+
+// urlPattern matches http(s) links up to the next whitespace, leaving out
+// trailing punctuation that usually ends the surrounding sentence.
+var urlPattern = regexp.MustCompile(`https?://[^\s]*[^\s.,;:!?)\]]`)
+
+// shortenLinks replaces every link in body with its shortened form. Links the
+// shortener fails on are kept as-is with a warning, so a shortener outage
+// never blocks a send. Once ctx is done the remaining links are left alone;
+// the caller is expected to abandon the send.
+func (c *Client) shortenLinks(ctx context.Context, body string) string {
+	if c.shortener == nil {
+		return body
+	}
+
+	return urlPattern.ReplaceAllStringFunc(body, func(link string) string {
+		if ctx.Err() != nil {
+			return link
+		}
+		short, err := c.shortener.Shorten(ctx, link)
+		if err != nil {
+			c.logger.Warn("failed to shorten link, keeping original", "url", link, "error", err)
+			return link
+		}
+		return short
+	})
+}
@@ -0,0 +1,52 @@
+package messaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+)
+
+// signatureLength is the number of characters in a verification code.
+const signatureLength = 8
+
+// Signer derives per-tenant verification codes that customers can use to
+// confirm a message is authentic. A code is an HMAC of the customer and
+// journey under the tenant's key, so it is stable for a customer within a
+// journey and cannot be forged without the key.
+type Signer struct {
+	keys map[string][]byte
+}
+
+// NewSigner creates a signer from a map of tenant ID to signing key.
+func NewSigner(keys map[string]string) *Signer {
+	s := &Signer{keys: make(map[string][]byte, len(keys))}
+	for tenantID, key := range keys {
+		s.keys[tenantID] = []byte(key)
+	}
+	return s
+}
+
+// code returns the verification code for a customer in a journey, or false
+// when the tenant has no signing key.
+func (s *Signer) code(tenantID, customerNumber, journeyID string) (string, bool) {
+	key, ok := s.keys[tenantID]
+	if !ok {
+		return "", false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(customerNumber + "|" + journeyID))
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil))
+	return encoded[:signatureLength], true
+}
+
+// sign appends the tenant's verification code to body. Bodies of tenants
+// without a signing key are returned unchanged.
+func (s *Signer) sign(body, tenantID, customerNumber, journeyID string) string {
+	code, ok := s.code(tenantID, customerNumber, journeyID)
+	if !ok {
+		return body
+	}
+	return fmt.Sprintf("%s\n\nVerification code: %s", body, code)
+}
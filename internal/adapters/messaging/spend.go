@@ -0,0 +1,83 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+	"worker-project/internal/ports"
+)
+
+// SpendLimiter caps message spend per run and per day. The run tally is kept
+// in memory; the daily tally is kept in the ledger so it spans runs.
+type SpendLimiter struct {
+	ledger   ports.SpendLedger
+	costs    map[string]float64
+	runCap   float64
+	dailyCap float64
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	runSpend float64
+}
+
+// NewSpendLimiter creates a spend limiter backed by a ledger.
+func NewSpendLimiter(ledger ports.SpendLedger, cfg config.SpendSettings, logger *slog.Logger) *SpendLimiter {
+	return &SpendLimiter{
+		ledger:   ledger,
+		costs:    cfg.Costs,
+		runCap:   cfg.RunCap,
+		dailyCap: cfg.DailyCap,
+		logger:   logger,
+	}
+}
+
+// check returns the cost of sending parts messages of a category, or
+// domain.ErrSpendCapReached when the send would exceed a cap. Ledger failures
+// are logged and the daily cap is not enforced, so an unavailable ledger never
+// blocks recovery.
+func (l *SpendLimiter) check(ctx context.Context, category string, parts int) (float64, error) {
+	cost := l.costs[category] * float64(parts)
+	if cost == 0 {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	runSpend := l.runSpend
+	l.mu.Unlock()
+
+	if l.runCap > 0 && runSpend+cost > l.runCap {
+		return 0, fmt.Errorf("%w: run spend %.4f of %.4f", domain.ErrSpendCapReached, runSpend, l.runCap)
+	}
+
+	if l.dailyCap > 0 {
+		dailySpend, err := l.ledger.DailySpend(ctx)
+		if err != nil {
+			l.logger.Warn("failed to check daily spend", "error", err)
+			return cost, nil
+		}
+		if dailySpend+cost > l.dailyCap {
+			return 0, fmt.Errorf("%w: daily spend %.4f of %.4f", domain.ErrSpendCapReached, dailySpend, l.dailyCap)
+		}
+	}
+
+	return cost, nil
+}
+
+// record adds the cost of a completed send to the run and daily tallies.
+func (l *SpendLimiter) record(ctx context.Context, cost float64) {
+	if cost == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.runSpend += cost
+	l.mu.Unlock()
+
+	if _, err := l.ledger.AddSpend(ctx, cost); err != nil {
+		l.logger.Warn("failed to record daily spend", "cost", cost, "error", err)
+	}
+}
@@ -0,0 +1,110 @@
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"worker-project/internal/domain"
+	"worker-project/internal/ports"
+)
+
+const (
+	// MaxBodyLength is the maximum number of characters in a WhatsApp text body.
+	MaxBodyLength = 4096
+
+	// defaultMaxParts is used when splitting is enabled without a max_parts value.
+	defaultMaxParts = 3
+
+	// partPrefixReserve is the room kept for a "(n/m) " prefix on numbered parts.
+	partPrefixReserve = len("(99/99) ")
+)
+
+// splitBody returns the parts a rendered body should be sent as.
+// Bodies within limit are returned unchanged as a single part.
+func splitBody(body string, limit int, opts ports.SplitOptions) ([]string, error) {
+	length := utf8.RuneCountInString(body)
+	if length <= limit {
+		return []string{body}, nil
+	}
+
+	if !opts.Enabled {
+		return nil, fmt.Errorf("%w: %d characters (limit %d)", domain.ErrMessageTooLong, length, limit)
+	}
+
+	maxParts := opts.MaxParts
+	if maxParts <= 0 {
+		maxParts = defaultMaxParts
+	}
+
+	partLimit := limit
+	if opts.Numbered {
+		partLimit -= partPrefixReserve
+	}
+
+	var parts []string
+	var current strings.Builder
+	for _, sentence := range splitSentences(body) {
+		if utf8.RuneCountInString(current.String())+utf8.RuneCountInString(sentence) > partLimit {
+			if current.Len() > 0 {
+				parts = append(parts, strings.TrimSpace(current.String()))
+				current.Reset()
+			}
+			if utf8.RuneCountInString(sentence) > partLimit {
+				return nil, fmt.Errorf("%w: sentence of %d characters cannot be split (limit %d)",
+					domain.ErrMessageTooLong, utf8.RuneCountInString(sentence), partLimit)
+			}
+		}
+		current.WriteString(sentence)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		parts = append(parts, strings.TrimSpace(current.String()))
+	}
+
+	if len(parts) > maxParts {
+		return nil, fmt.Errorf("%w: needs %d parts (max %d)", domain.ErrMessageTooLong, len(parts), maxParts)
+	}
+
+	if opts.Numbered {
+		for i := range parts {
+			parts[i] = fmt.Sprintf("(%d/%d) %s", i+1, len(parts), parts[i])
+		}
+	}
+
+	return parts, nil
+}
+
+// splitSentences splits text after sentence terminators or line breaks,
+// keeping the trailing whitespace with the preceding sentence.
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.', '!', '?', '\n':
+		default:
+			continue
+		}
+
+		end := i + 1
+		if end < len(runes) && !unicode.IsSpace(runes[end]) && runes[i] != '\n' {
+			continue
+		}
+		for end < len(runes) && unicode.IsSpace(runes[end]) {
+			end++
+		}
+
+		sentences = append(sentences, string(runes[start:end]))
+		start = end
+		i = end - 1
+	}
+
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+
+	return sentences
+}
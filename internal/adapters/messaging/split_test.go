@@ -0,0 +1,65 @@
+package messaging
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"worker-project/internal/domain"
+	"worker-project/internal/ports"
+)
+
+func TestSplitBody(t *testing.T) {
+	const body = "Your cart is waiting. Items sell out fast!"
+
+	tests := []struct {
+		name    string
+		limit   int
+		opts    ports.SplitOptions
+		want    []string
+		wantErr bool
+	}{
+		{name: "within limit", limit: 100, want: []string{body}},
+		{name: "too long without splitting", limit: 30, wantErr: true},
+		{
+			name:  "splits into two parts",
+			limit: 30,
+			opts:  ports.SplitOptions{Enabled: true},
+			want:  []string{"Your cart is waiting.", "Items sell out fast!"},
+		},
+		{
+			name:  "numbered parts",
+			limit: 30,
+			opts:  ports.SplitOptions{Enabled: true, Numbered: true},
+			want:  []string{"(1/2) Your cart is waiting.", "(2/2) Items sell out fast!"},
+		},
+		{name: "exceeds max parts", limit: 30, opts: ports.SplitOptions{Enabled: true, MaxParts: 1}, wantErr: true},
+		{name: "sentence longer than a part", limit: 15, opts: ports.SplitOptions{Enabled: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := splitBody(body, tt.limit, tt.opts)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrMessageTooLong) {
+					t.Fatalf("expected ErrMessageTooLong, got parts %q and error %v", parts, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitBody: %v", err)
+			}
+			if !slices.Equal(parts, tt.want) {
+				t.Errorf("parts = %q, want %q", parts, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	got := splitSentences("Hi there! Price is 1.99 today.\nSee you? Bye")
+	want := []string{"Hi there! ", "Price is 1.99 today.\n", "See you? ", "Bye"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sentences = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ScanBaseline implements ports.ScanBaseline with a capped Redis list.
+type ScanBaseline struct {
+	client *Client
+	keys   KeyBuilder
+	size   int64
+}
+
+// NewScanBaseline creates a baseline that keeps the counts of the last size runs.
+func NewScanBaseline(client *Client, keys KeyBuilder, size int) *ScanBaseline {
+	return &ScanBaseline{
+		client: client,
+		keys:   keys,
+		size:   int64(size),
+	}
+}
+
+// RecentCounts returns the scanned journey counts of recent runs, newest first.
+func (b *ScanBaseline) RecentCounts(ctx context.Context) ([]int, error) {
+	values, err := b.client.LRange(ctx, b.keys.ScanBaselineKey(), 0, b.size-1)
+	if err != nil {
+		return nil, fmt.Errorf("get scan baseline: %w", err)
+	}
+
+	counts := make([]int, 0, len(values))
+	for _, v := range values {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse scan baseline: %w", err)
+		}
+		counts = append(counts, n)
+	}
+	return counts, nil
+}
+
+// RecordCount records the scanned journey count of the current run.
+func (b *ScanBaseline) RecordCount(ctx context.Context, count int) error {
+	if err := b.client.PushCapped(ctx, b.keys.ScanBaselineKey(), strconv.Itoa(count), b.size); err != nil {
+		return fmt.Errorf("record scan baseline: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,10 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -9,45 +13,117 @@ import (
 	"worker-project/internal/config"
 )
 
-// Key patterns for Redis keys.
-const (
-	KeyPatternJourneyState    = "journey:%s:%s:state"
-	KeyPatternJourneyRepiques = "journey:%s:%s:repiques"
-)
-
 // Client wraps a Redis client with configuration.
 type Client struct {
-	native *redis.Client
+	native redis.UniversalClient
 }
 
 // NewClient creates a new Redis client with the given configuration.
-func NewClient(cfg config.RedisConfig) (*Client, error) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         cfg.Addr,
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  cfg.DialTimeout,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		PoolSize:     cfg.PoolSize,
-		MinIdleConns: cfg.MinIdleConns,
-	})
-
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
-	defer cancel()
+// In cluster mode, Addr may hold a comma-separated list of seed nodes.
+// Transient connection failures are retried; see connect.
+func NewClient(ctx context.Context, cfg config.RedisConfig, logger *slog.Logger) (*Client, error) {
+	var rdb redis.UniversalClient
+	if cfg.ClusterMode {
+		rdb = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        strings.Split(cfg.Addr, ","),
+			Password:     cfg.Password,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+		})
+	} else {
+		rdb = redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+		})
+	}
 
-	if err := rdb.Ping(ctx).Err(); err != nil {
+	if err := connect(ctx, rdb, cfg, logger); err != nil {
+		_ = rdb.Close()
 		return nil, err
 	}
 
 	return &Client{native: rdb}, nil
 }
 
-// Native returns the underlying redis.Client for advanced operations.
-func (c *Client) Native() *redis.Client {
+// connect pings Redis until it answers, retrying transient failures up to
+// cfg.ConnectRetries times with a doubling backoff from cfg.ConnectBackoff.
+// Errors replied by the server, such as a wrong password, are permanent and
+// returned at once.
+func connect(ctx context.Context, rdb redis.UniversalClient, cfg config.RedisConfig, logger *slog.Logger) error {
+	backoff := cfg.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, cfg.DialTimeout)
+		err := rdb.Ping(pingCtx).Err()
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt >= cfg.ConnectRetries {
+			return fmt.Errorf("connect after %d attempts: %w", attempt+1, err)
+		}
+
+		logger.Warn("redis connect failed, retrying",
+			"addr", cfg.Addr,
+			"attempt", attempt+1,
+			"error", err,
+			"backoff", backoff,
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether a connection error may clear on retry. Errors
+// replied by the server are permanent, except while it is loading its dataset.
+func isTransient(err error) bool {
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		return strings.HasPrefix(replyErr.Error(), "LOADING")
+	}
+	return true
+}
+
+// NewReadClient creates a client for read-only traffic. It connects to the
+// configured read replica, or returns primary when no replica is configured.
+func NewReadClient(ctx context.Context, cfg config.RedisConfig, primary *Client, logger *slog.Logger) (*Client, error) {
+	if cfg.ReadAddr == "" {
+		return primary, nil
+	}
+
+	replicaCfg := cfg
+	replicaCfg.Addr = cfg.ReadAddr
+	return NewClient(ctx, replicaCfg, logger)
+}
+
+// Native returns the underlying client for advanced operations.
+// In cluster mode it is a *redis.ClusterClient, otherwise a *redis.Client.
+func (c *Client) Native() redis.UniversalClient {
 	return c.native
 }
 
+// Ping checks that Redis is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.native.Ping(ctx).Err()
+}
+
 // Get retrieves a value by key.
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return c.native.Get(ctx, key).Result()
@@ -58,11 +134,134 @@ func (c *Client) Set(ctx context.Context, key, value string, expiration time.Dur
 	return c.native.Set(ctx, key, value, expiration).Err()
 }
 
+// GetSet sets a key and returns its previous value, or redis.Nil if it had none.
+func (c *Client) GetSet(ctx context.Context, key, value string) (string, error) {
+	return c.native.GetSet(ctx, key, value).Result()
+}
+
 // Del deletes keys.
 func (c *Client) Del(ctx context.Context, keys ...string) error {
 	return c.native.Del(ctx, keys...).Err()
 }
 
+// Incr increments a counter and refreshes its expiration.
+func (c *Client) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.native.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// IncrByFloat adds to a floating-point counter and refreshes its expiration.
+func (c *Client) IncrByFloat(ctx context.Context, key string, value float64, expiration time.Duration) (float64, error) {
+	var incr *redis.FloatCmd
+	_, err := c.native.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrByFloat(ctx, key, value)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// SetNX stores a value with an expiration only if the key does not exist.
+// It reports whether the value was stored.
+func (c *Client) SetNX(ctx context.Context, key, value string, expiration time.Duration) (bool, error) {
+	return c.native.SetNX(ctx, key, value, expiration).Result()
+}
+
+// delIfEqualScript deletes a key only while it still holds the expected value.
+var delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DelIfEqual atomically deletes a key if it holds the given value.
+func (c *Client) DelIfEqual(ctx context.Context, key, value string) error {
+	return delIfEqualScript.Run(ctx, c.native, []string{key}, value).Err()
+}
+
+// setIfEqualScript replaces a key's value, keeping its TTL, only while it
+// still holds the expected value.
+var setIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("SET", KEYS[1], ARGV[2], "KEEPTTL")
+end
+return nil
+`)
+
+// SetIfEqual atomically replaces a key's value if it holds the expected one,
+// preserving its TTL.
+func (c *Client) SetIfEqual(ctx context.Context, key, expected, value string) error {
+	err := setIfEqualScript.Run(ctx, c.native, []string{key}, expected, value).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// expireIfEqualScript resets a key's TTL only while it holds the expected value.
+var expireIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// ExpireIfEqual atomically resets a key's expiration if it holds the given
+// value. It reports whether the key still held the value.
+func (c *Client) ExpireIfEqual(ctx context.Context, key, value string, expiration time.Duration) (bool, error) {
+	n, err := expireIfEqualScript.Run(ctx, c.native, []string{key}, value, expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// PushCapped prepends a value to a list and trims it to at most size entries.
+func (c *Client) PushCapped(ctx context.Context, key, value string, size int64) error {
+	_, err := c.native.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, value)
+		pipe.LTrim(ctx, key, 0, size-1)
+		return nil
+	})
+	return err
+}
+
+// LRange returns the list entries between start and stop, inclusive.
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return c.native.LRange(ctx, key, start, stop).Result()
+}
+
+// XAdd appends an entry to a stream, approximately trimming it to maxLen entries.
+func (c *Client) XAdd(ctx context.Context, stream string, values map[string]any, maxLen int64) error {
+	return c.native.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+// Exists reports whether a key exists.
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.native.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 // Close closes the Redis connection.
 func (c *Client) Close() error {
 	return c.native.Close()
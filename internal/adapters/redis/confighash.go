@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ConfigHashStore implements ports.ConfigHashStore using one Redis key per journey.
+type ConfigHashStore struct {
+	client *Client
+	keys   KeyBuilder
+}
+
+// NewConfigHashStore creates a new Redis config hash store.
+func NewConfigHashStore(client *Client, keys KeyBuilder) *ConfigHashStore {
+	return &ConfigHashStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// SwapConfigHash stores hash as the journey's current config hash and
+// returns the previous one.
+func (s *ConfigHashStore) SwapConfigHash(ctx context.Context, journeyID, hash string) (string, bool, error) {
+	old, err := s.client.GetSet(ctx, s.keys.ConfigHashKey(journeyID), hash)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("swap config hash: %w", err)
+	}
+	return old, true, nil
+}
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"worker-project/internal/domain"
+)
+
+// eventStreamMaxLen approximately caps the event stream so it cannot grow
+// without bound when no consumer trims it.
+const eventStreamMaxLen = 100000
+
+// EventStream implements ports.EventPublisher by appending events to a Redis
+// stream. Each entry holds the event type and its JSON encoding.
+type EventStream struct {
+	client *Client
+	keys   KeyBuilder
+}
+
+// NewEventStream creates a new Redis stream event publisher.
+func NewEventStream(client *Client, keys KeyBuilder) *EventStream {
+	return &EventStream{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Publish appends a lifecycle event to the stream.
+func (s *EventStream) Publish(ctx context.Context, event domain.LifecycleEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	values := map[string]any{
+		"type":  event.Type,
+		"event": string(data),
+	}
+	if err := s.client.XAdd(ctx, s.keys.EventStreamKey(), values, eventStreamMaxLen); err != nil {
+		return fmt.Errorf("publish event: %w", err)
+	}
+	return nil
+}
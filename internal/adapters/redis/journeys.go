@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// JourneyTracker implements ports.JourneyTracker using one Redis key per journey.
+type JourneyTracker struct {
+	client *Client
+	keys   KeyBuilder
+}
+
+// NewJourneyTracker creates a new Redis journey tracker.
+func NewJourneyTracker(client *Client, keys KeyBuilder) *JourneyTracker {
+	return &JourneyTracker{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// FirstSeen records now as the journey's first-seen time unless one is
+// already recorded, and returns the recorded time.
+func (t *JourneyTracker) FirstSeen(ctx context.Context, journeyID string, now time.Time) (time.Time, error) {
+	key := t.keys.JourneyFirstSeenKey(journeyID)
+
+	set, err := t.client.SetNX(ctx, key, now.UTC().Format(time.RFC3339Nano), 0)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("record journey first seen: %w", err)
+	}
+	if set {
+		return now, nil
+	}
+
+	data, err := t.client.Get(ctx, key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get journey first seen: %w", err)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, data)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse journey first seen: %w", err)
+	}
+	return at, nil
+}
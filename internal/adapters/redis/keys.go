@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyBuilder derives every Redis key used by the worker, so the scanner,
+// repository and kill-switch cannot drift apart. An optional prefix namespaces
+// all keys (e.g. "staging" yields "staging:journey:{id}:{customer}:state").
+type KeyBuilder struct {
+	prefix string
+}
+
+// NewKeyBuilder creates a key builder with an optional namespace prefix.
+func NewKeyBuilder(prefix string) KeyBuilder {
+	if prefix != "" {
+		prefix += ":"
+	}
+	return KeyBuilder{prefix: prefix}
+}
+
+// StateKey returns the key holding a customer's journey state.
+func (k KeyBuilder) StateKey(journeyID, customerNumber string) string {
+	return fmt.Sprintf("%sjourney:%s:%s:state", k.prefix, journeyID, customerNumber)
+}
+
+// RepiquesKey returns the key holding a customer's repique attempt counts.
+func (k KeyBuilder) RepiquesKey(journeyID, customerNumber string) string {
+	return fmt.Sprintf("%sjourney:%s:%s:repiques", k.prefix, journeyID, customerNumber)
+}
+
+// FailuresKey returns the key counting consecutive processing failures.
+func (k KeyBuilder) FailuresKey(journeyID, customerNumber string) string {
+	return fmt.Sprintf("%sjourney:%s:%s:failures", k.prefix, journeyID, customerNumber)
+}
+
+// DeadLetterKey returns the key holding a dead-lettered journey state.
+// It is deliberately outside the state scan pattern.
+func (k KeyBuilder) DeadLetterKey(journeyID, customerNumber string) string {
+	return fmt.Sprintf("%sjourney:%s:%s:deadletter", k.prefix, journeyID, customerNumber)
+}
+
+// TenantSendsKey returns the key counting a tenant's sends on a given day.
+func (k KeyBuilder) TenantSendsKey(tenantID string, day time.Time) string {
+	return fmt.Sprintf("%stenant:%s:sends:%s", k.prefix, tenantID, day.UTC().Format("2006-01-02"))
+}
+
+// CustomerSendsKey returns the key counting a customer's sends across all
+// journeys on a given day.
+func (k KeyBuilder) CustomerSendsKey(customerNumber string, day time.Time) string {
+	return fmt.Sprintf("%scustomer:%s:sends:%s", k.prefix, customerNumber, day.UTC().Format("2006-01-02"))
+}
+
+// SpendKey returns the key accumulating message spend on a given day.
+func (k KeyBuilder) SpendKey(day time.Time) string {
+	return fmt.Sprintf("%sspend:%s", k.prefix, day.UTC().Format("2006-01-02"))
+}
+
+// CustomerLockKey returns the key locking a customer for processing.
+func (k KeyBuilder) CustomerLockKey(customerNumber string) string {
+	return fmt.Sprintf("%slock:customer:%s", k.prefix, customerNumber)
+}
+
+// ScanBaselineKey returns the key listing the scanned journey counts of recent runs.
+func (k KeyBuilder) ScanBaselineKey() string {
+	return k.prefix + "worker:scan_baseline"
+}
+
+// LastRunKey returns the key holding the completion time of the last successful run.
+func (k KeyBuilder) LastRunKey() string {
+	return k.prefix + "worker:last_run"
+}
+
+// JourneyFirstSeenKey returns the key holding when the worker first saw a journey.
+func (k KeyBuilder) JourneyFirstSeenKey(journeyID string) string {
+	return fmt.Sprintf("%sworker:first_seen:%s", k.prefix, journeyID)
+}
+
+// ConfigHashKey returns the key holding the hash of a journey's last loaded config.
+func (k KeyBuilder) ConfigHashKey(journeyID string) string {
+	return fmt.Sprintf("%sworker:config_hash:%s", k.prefix, journeyID)
+}
+
+// EventStreamKey returns the stream receiving journey lifecycle events.
+func (k KeyBuilder) EventStreamKey() string {
+	return k.prefix + "events:journey"
+}
+
+// KillSwitchKey returns the key that disables a repique when present.
+func (k KeyBuilder) KillSwitchKey(journeyID, repiqueID string) string {
+	return fmt.Sprintf("%skillswitch:%s:%s", k.prefix, journeyID, repiqueID)
+}
+
+// StateScanPattern returns the SCAN pattern matching state keys of a journey,
+// or of all journeys when journeyID is empty.
+func (k KeyBuilder) StateScanPattern(journeyID string) string {
+	if journeyID == "" {
+		journeyID = "*"
+	}
+	return fmt.Sprintf("%sjourney:%s:*:state", k.prefix, journeyID)
+}
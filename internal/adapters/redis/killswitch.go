@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KillSwitch implements ports.KillSwitch using Redis keys.
+// Lookups are cached for a short period to avoid a Redis call per evaluation.
+type KillSwitch struct {
+	client   *Client
+	keys     KeyBuilder
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]killSwitchEntry
+}
+
+type killSwitchEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+// NewKillSwitch creates a new Redis-backed kill-switch.
+func NewKillSwitch(client *Client, keys KeyBuilder, cacheTTL time.Duration) *KillSwitch {
+	return &KillSwitch{
+		client:   client,
+		keys:     keys,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]killSwitchEntry),
+	}
+}
+
+// IsActive reports whether the kill-switch for a repique is set.
+func (k *KillSwitch) IsActive(ctx context.Context, journeyID, repiqueID string) (bool, error) {
+	key := k.keys.KillSwitchKey(journeyID, repiqueID)
+
+	k.mu.Lock()
+	entry, ok := k.cache[key]
+	k.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.active, nil
+	}
+
+	active, err := k.client.Exists(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("check kill-switch: %w", err)
+	}
+
+	k.mu.Lock()
+	k.cache[key] = killSwitchEntry{active: active, expiresAt: time.Now().Add(k.cacheTTL)}
+	k.mu.Unlock()
+
+	return active, nil
+}
+
+// Activate sets the kill-switch for a repique.
+func (k *KillSwitch) Activate(ctx context.Context, journeyID, repiqueID string) error {
+	key := k.keys.KillSwitchKey(journeyID, repiqueID)
+	if err := k.client.Set(ctx, key, "1", 0); err != nil {
+		return fmt.Errorf("activate kill-switch: %w", err)
+	}
+	k.forget(key)
+	return nil
+}
+
+// Deactivate clears the kill-switch for a repique.
+func (k *KillSwitch) Deactivate(ctx context.Context, journeyID, repiqueID string) error {
+	key := k.keys.KillSwitchKey(journeyID, repiqueID)
+	if err := k.client.Del(ctx, key); err != nil {
+		return fmt.Errorf("deactivate kill-switch: %w", err)
+	}
+	k.forget(key)
+	return nil
+}
+
+func (k *KillSwitch) forget(key string) {
+	k.mu.Lock()
+	delete(k.cache, key)
+	k.mu.Unlock()
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"worker-project/internal/domain"
+)
+
+// CustomerLock implements ports.CustomerLocker with SET NX keys. Each lock
+// holds a random token so a worker only releases a lock it still owns; the
+// TTL frees locks left behind by crashed workers. A held lock is refreshed
+// every third of its TTL, so processing that outlasts the TTL (for example
+// through send jitter) keeps the customer locked.
+type CustomerLock struct {
+	client *Client
+	keys   KeyBuilder
+	ttl    time.Duration
+	logger *slog.Logger
+}
+
+// NewCustomerLock creates a new Redis-backed customer lock.
+func NewCustomerLock(client *Client, keys KeyBuilder, ttl time.Duration, logger *slog.Logger) *CustomerLock {
+	return &CustomerLock{
+		client: client,
+		keys:   keys,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Acquire takes the customer's processing lock. It returns
+// domain.ErrLockHeld when another worker holds the lock.
+func (l *CustomerLock) Acquire(ctx context.Context, customerNumber string) (func(context.Context) error, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, fmt.Errorf("acquire customer lock: %w", err)
+	}
+
+	key := l.keys.CustomerLockKey(customerNumber)
+	ok, err := l.client.SetNX(ctx, key, token, l.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("acquire customer lock: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrLockHeld
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.refresh(context.WithoutCancel(ctx), key, token, stop)
+	}()
+
+	var once sync.Once
+	release := func(ctx context.Context) error {
+		once.Do(func() { close(stop) })
+		<-done
+		if err := l.client.DelIfEqual(ctx, key, token); err != nil {
+			return fmt.Errorf("release customer lock: %w", err)
+		}
+		return nil
+	}
+	return release, nil
+}
+
+// refresh extends the lock's TTL until stop is closed or the lock is lost.
+func (l *CustomerLock) refresh(ctx context.Context, key, token string, stop <-chan struct{}) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		held, err := l.client.ExpireIfEqual(ctx, key, token, l.ttl)
+		if err != nil {
+			l.logger.Warn("failed to refresh customer lock", "key", key, "error", err)
+			continue
+		}
+		if !held {
+			l.logger.Warn("customer lock lost before release", "key", key)
+			return
+		}
+	}
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
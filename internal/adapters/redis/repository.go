@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,23 +14,34 @@ import (
 	"worker-project/internal/domain"
 )
 
+// sendCountTTL keeps daily send counters past the end of their day.
+const sendCountTTL = 48 * time.Hour
+
+// failureCountTTL frees failure counts of journeys that stop being processed
+// before they fail again or succeed.
+const failureCountTTL = 48 * time.Hour
+
 // Repository implements ports.StateRepository using Redis.
 type Repository struct {
 	client *Client
+	keys   KeyBuilder
 	ttl    time.Duration
+	logger *slog.Logger
 }
 
 // NewRepository creates a new Redis repository.
-func NewRepository(client *Client, ttl time.Duration) *Repository {
+func NewRepository(client *Client, keys KeyBuilder, ttl time.Duration, logger *slog.Logger) *Repository {
 	return &Repository{
 		client: client,
+		keys:   keys,
 		ttl:    ttl,
+		logger: logger,
 	}
 }
 
 // GetJourneyState retrieves the current state of a customer's journey.
 func (r *Repository) GetJourneyState(ctx context.Context, journeyID, customerNumber string) (*domain.JourneyState, error) {
-	key := fmt.Sprintf(KeyPatternJourneyState, journeyID, customerNumber)
+	key := r.keys.StateKey(journeyID, customerNumber)
 
 	data, err := r.client.Get(ctx, key)
 	if err != nil {
@@ -43,12 +56,31 @@ func (r *Repository) GetJourneyState(ctx context.Context, journeyID, customerNum
 		return nil, fmt.Errorf("unmarshal journey state: %w", err)
 	}
 
+	if state.Upgrade() {
+		r.writeBackUpgrade(ctx, key, data, &state)
+	}
+
 	return &state, nil
 }
 
+// writeBackUpgrade stores an upgraded legacy state, unless the event-tracker
+// rewrote the key since it was read. Failures are logged and the upgrade is
+// retried on the next read.
+func (r *Repository) writeBackUpgrade(ctx context.Context, key, legacy string, state *domain.JourneyState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		r.logger.Warn("failed to marshal upgraded journey state", "key", key, "error", err)
+		return
+	}
+
+	if err := r.client.SetIfEqual(ctx, key, legacy, string(data)); err != nil {
+		r.logger.Warn("failed to write back upgraded journey state", "key", key, "error", err)
+	}
+}
+
 // GetRepiqueAttempts retrieves repique attempt counts for a customer's journey.
 func (r *Repository) GetRepiqueAttempts(ctx context.Context, journeyID, customerNumber string) (*domain.RepiqueAttempts, error) {
-	key := fmt.Sprintf(KeyPatternJourneyRepiques, journeyID, customerNumber)
+	key := r.keys.RepiquesKey(journeyID, customerNumber)
 
 	data, err := r.client.Get(ctx, key)
 	if err != nil {
@@ -84,7 +116,7 @@ func (r *Repository) IncrementRepiqueAttempt(ctx context.Context, journeyID, cus
 		return fmt.Errorf("marshal repique attempts: %w", err)
 	}
 
-	key := fmt.Sprintf(KeyPatternJourneyRepiques, journeyID, customerNumber)
+	key := r.keys.RepiquesKey(journeyID, customerNumber)
 	if err := r.client.Set(ctx, key, string(data), r.ttl); err != nil {
 		return fmt.Errorf("save repique attempts: %w", err)
 	}
@@ -92,9 +124,145 @@ func (r *Repository) IncrementRepiqueAttempt(ctx context.Context, journeyID, cus
 	return nil
 }
 
+// IncrementFailureCount increments the consecutive processing failure count
+// of a customer's journey and returns the new count.
+func (r *Repository) IncrementFailureCount(ctx context.Context, journeyID, customerNumber string) (int, error) {
+	count, err := r.client.Incr(ctx, r.keys.FailuresKey(journeyID, customerNumber), failureCountTTL)
+	if err != nil {
+		return 0, fmt.Errorf("increment failure count: %w", err)
+	}
+	return int(count), nil
+}
+
+// ResetFailureCount clears the consecutive processing failure count. Most
+// journeys have none, so the key is checked before anything is written.
+func (r *Repository) ResetFailureCount(ctx context.Context, journeyID, customerNumber string) error {
+	key := r.keys.FailuresKey(journeyID, customerNumber)
+
+	exists, err := r.client.Exists(ctx, key)
+	if err != nil {
+		return fmt.Errorf("check failure count: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	if err := r.client.Del(ctx, key); err != nil {
+		return fmt.Errorf("reset failure count: %w", err)
+	}
+	return nil
+}
+
+// IncrementTenantSendCount increments today's send count of a tenant and
+// returns the new count.
+func (r *Repository) IncrementTenantSendCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := r.client.Incr(ctx, r.keys.TenantSendsKey(tenantID, time.Now()), sendCountTTL)
+	if err != nil {
+		return 0, fmt.Errorf("increment tenant send count: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetTenantSendCount returns today's send count of a tenant.
+func (r *Repository) GetTenantSendCount(ctx context.Context, tenantID string) (int, error) {
+	data, err := r.client.Get(ctx, r.keys.TenantSendsKey(tenantID, time.Now()))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get tenant send count: %w", err)
+	}
+
+	count, err := strconv.Atoi(data)
+	if err != nil {
+		return 0, fmt.Errorf("parse tenant send count: %w", err)
+	}
+	return count, nil
+}
+
+// IncrementCustomerSendCount increments today's send count of a customer
+// across all journeys and returns the new count.
+func (r *Repository) IncrementCustomerSendCount(ctx context.Context, customerNumber string) (int, error) {
+	count, err := r.client.Incr(ctx, r.keys.CustomerSendsKey(customerNumber, time.Now()), sendCountTTL)
+	if err != nil {
+		return 0, fmt.Errorf("increment customer send count: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetCustomerSendCount returns today's send count of a customer across all journeys.
+func (r *Repository) GetCustomerSendCount(ctx context.Context, customerNumber string) (int, error) {
+	data, err := r.client.Get(ctx, r.keys.CustomerSendsKey(customerNumber, time.Now()))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get customer send count: %w", err)
+	}
+
+	count, err := strconv.Atoi(data)
+	if err != nil {
+		return 0, fmt.Errorf("parse customer send count: %w", err)
+	}
+	return count, nil
+}
+
+// DeadLetterJourney moves a journey state to its dead-letter key, so it is no
+// longer scanned but remains available for inspection. Keys are copied rather
+// than renamed because they may live in different cluster slots.
+func (r *Repository) DeadLetterJourney(ctx context.Context, journeyID, customerNumber string) error {
+	stateKey := r.keys.StateKey(journeyID, customerNumber)
+
+	data, err := r.client.Get(ctx, stateKey)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("get journey state: %w", err)
+	}
+
+	if err := r.client.Set(ctx, r.keys.DeadLetterKey(journeyID, customerNumber), data, r.ttl); err != nil {
+		return fmt.Errorf("save dead-letter state: %w", err)
+	}
+
+	if err := r.client.Del(ctx, stateKey); err != nil {
+		return fmt.Errorf("delete journey state: %w", err)
+	}
+
+	return r.ResetFailureCount(ctx, journeyID, customerNumber)
+}
+
+// ForceAge rewrites a journey state so that it appears to have been inactive
+// for the given duration, by moving LastInteractionAt and StepStartedAt back
+// to now minus age. The key's TTL is preserved. Intended for tests and QA only.
+func (r *Repository) ForceAge(ctx context.Context, journeyID, customerNumber string, age time.Duration) error {
+	state, err := r.GetJourneyState(ctx, journeyID, customerNumber)
+	if err != nil {
+		return err
+	}
+
+	state.ForceAge(age)
+	return r.saveJourneyState(ctx, state)
+}
+
+// saveJourneyState overwrites a journey state, preserving the key's TTL.
+func (r *Repository) saveJourneyState(ctx context.Context, state *domain.JourneyState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal journey state: %w", err)
+	}
+
+	key := r.keys.StateKey(state.JourneyID, state.CustomerNumber)
+	if err := r.client.Set(ctx, key, string(data), redis.KeepTTL); err != nil {
+		return fmt.Errorf("save journey state: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteJourneyState removes a journey state.
 func (r *Repository) DeleteJourneyState(ctx context.Context, journeyID, customerNumber string) error {
-	key := fmt.Sprintf(KeyPatternJourneyState, journeyID, customerNumber)
+	key := r.keys.StateKey(journeyID, customerNumber)
 	if err := r.client.Del(ctx, key); err != nil {
 		return fmt.Errorf("delete journey state: %w", err)
 	}
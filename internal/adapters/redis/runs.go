@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RunTracker implements ports.RunTracker using a Redis key.
+type RunTracker struct {
+	client *Client
+	keys   KeyBuilder
+}
+
+// NewRunTracker creates a new Redis run tracker.
+func NewRunTracker(client *Client, keys KeyBuilder) *RunTracker {
+	return &RunTracker{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// LastRunAt returns the completion time of the last successful run.
+func (t *RunTracker) LastRunAt(ctx context.Context) (time.Time, bool, error) {
+	data, err := t.client.Get(ctx, t.keys.LastRunKey())
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("get last run: %w", err)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, data)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse last run: %w", err)
+	}
+	return at, true, nil
+}
+
+// RecordRun records the completion time of a successful run.
+func (t *RunTracker) RecordRun(ctx context.Context, at time.Time) error {
+	if err := t.client.Set(ctx, t.keys.LastRunKey(), at.UTC().Format(time.RFC3339Nano), 0); err != nil {
+		return fmt.Errorf("record run: %w", err)
+	}
+	return nil
+}
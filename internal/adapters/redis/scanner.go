@@ -5,70 +5,222 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 
 	"worker-project/internal/domain"
 )
 
 // Scanner implements ports.JourneyScanner using Redis.
 type Scanner struct {
-	client    *Client
-	scanCount int64
-	logger    *slog.Logger
+	client      *Client
+	keys        KeyBuilder
+	scanCount   int64
+	maxDuration time.Duration
+	logger      *slog.Logger
 }
 
-// NewScanner creates a new Redis scanner.
-func NewScanner(client *Client, scanCount int64, logger *slog.Logger) *Scanner {
+// NewScanner creates a new Redis scanner. Scans stop after maxDuration and
+// return the journeys found so far, marked Truncated; zero means no limit.
+func NewScanner(client *Client, keys KeyBuilder, scanCount int64, maxDuration time.Duration, logger *slog.Logger) *Scanner {
 	return &Scanner{
-		client:    client,
-		scanCount: scanCount,
-		logger:    logger,
+		client:      client,
+		keys:        keys,
+		scanCount:   scanCount,
+		maxDuration: maxDuration,
+		logger:      logger,
 	}
 }
 
-// ScanAllJourneys returns all active journey states.
-func (s *Scanner) ScanAllJourneys(ctx context.Context) ([]*domain.JourneyState, error) {
-	return s.scan(ctx, "journey:*:*:state")
+// lightJourneyState holds the journey state fields needed for repique evaluation.
+// Decoding into it skips building the metadata map.
+type lightJourneyState struct {
+	SchemaVersion     int       `json:"schema_version"`
+	JourneyID         string    `json:"journey_id"`
+	Step              string    `json:"step"`
+	CustomerNumber    string    `json:"customer_number"`
+	TenantID          string    `json:"tenant_id"`
+	ContactID         string    `json:"contact_id"`
+	LastInteractionAt time.Time `json:"last_interaction_at"`
+	StepStartedAt     time.Time `json:"step_started_at"`
+	JourneyStartedAt  time.Time `json:"journey_started_at"`
+	ConsentAt         time.Time `json:"consent_at"`
+}
+
+// ScanAllJourneys returns all active journey states and the scan's coverage.
+func (s *Scanner) ScanAllJourneys(ctx context.Context) ([]*domain.JourneyState, domain.ScanStats, error) {
+	return s.scan(ctx, s.keys.StateScanPattern(""), decodeFull)
+}
+
+// ScanAllJourneysLight returns all active journey states without metadata.
+// The returned states are marked Partial.
+func (s *Scanner) ScanAllJourneysLight(ctx context.Context) ([]*domain.JourneyState, domain.ScanStats, error) {
+	return s.scan(ctx, s.keys.StateScanPattern(""), decodeLight)
 }
 
 // ScanJourneys returns active journey states for a specific journey ID.
-func (s *Scanner) ScanJourneys(ctx context.Context, journeyID string) ([]*domain.JourneyState, error) {
-	pattern := fmt.Sprintf("journey:%s:*:state", journeyID)
-	return s.scan(ctx, pattern)
+func (s *Scanner) ScanJourneys(ctx context.Context, journeyID string) ([]*domain.JourneyState, domain.ScanStats, error) {
+	return s.scan(ctx, s.keys.StateScanPattern(journeyID), decodeFull)
+}
+
+func decodeFull(data []byte) (*domain.JourneyState, error) {
+	var journey domain.JourneyState
+	if err := json.Unmarshal(data, &journey); err != nil {
+		return nil, err
+	}
+	return &journey, nil
+}
+
+func decodeLight(data []byte) (*domain.JourneyState, error) {
+	var light lightJourneyState
+	if err := json.Unmarshal(data, &light); err != nil {
+		return nil, err
+	}
+	return &domain.JourneyState{
+		SchemaVersion:     light.SchemaVersion,
+		JourneyID:         light.JourneyID,
+		Step:              light.Step,
+		CustomerNumber:    light.CustomerNumber,
+		TenantID:          light.TenantID,
+		ContactID:         light.ContactID,
+		LastInteractionAt: light.LastInteractionAt,
+		StepStartedAt:     light.StepStartedAt,
+		JourneyStartedAt:  light.JourneyStartedAt,
+		ConsentAt:         light.ConsentAt,
+		Partial:           true,
+	}, nil
 }
 
 // scan is a helper that performs the actual Redis SCAN operation.
-func (s *Scanner) scan(ctx context.Context, pattern string) ([]*domain.JourneyState, error) {
+// SCAN only covers the slots of the node it runs on, so in cluster mode
+// every master is scanned.
+func (s *Scanner) scan(
+	ctx context.Context,
+	pattern string,
+	decode func([]byte) (*domain.JourneyState, error),
+) ([]*domain.JourneyState, domain.ScanStats, error) {
 	var journeys []*domain.JourneyState
+	var stats domain.ScanStats
+
+	var deadline time.Time
+	if s.maxDuration > 0 {
+		deadline = time.Now().Add(s.maxDuration)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	switch native := s.client.Native().(type) {
+	case *redis.ClusterClient:
+		var mu sync.Mutex
+		err := native.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			nodeJourneys, nodeStats, err := s.scanNode(ctx, node, pattern, deadline, decode)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			journeys = append(journeys, nodeJourneys...)
+			stats.Add(nodeStats)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, stats, err
+		}
+	case *redis.Client:
+		var err error
+		journeys, stats, err = s.scanNode(ctx, native, pattern, deadline, decode)
+		if err != nil {
+			return nil, stats, err
+		}
+	default:
+		return nil, stats, fmt.Errorf("scan redis keys: unsupported client type %T", native)
+	}
+
+	if stats.Truncated {
+		s.logger.Warn("scan stopped at duration budget, results are partial",
+			"pattern", pattern,
+			"count", len(journeys),
+			"max_duration", s.maxDuration,
+		)
+	}
+
+	s.logger.Debug("scan completed", "pattern", pattern, "count", len(journeys))
+	return journeys, stats, nil
+}
+
+// keyReader is the part of a Redis node client used to read journey keys.
+type keyReader interface {
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+}
+
+// scanNode scans the keys of a single Redis node. Once the deadline passes,
+// it stops and returns the journeys read so far, marked Truncated; a zero
+// deadline never expires.
+func (s *Scanner) scanNode(
+	ctx context.Context,
+	node keyReader,
+	pattern string,
+	deadline time.Time,
+	decode func([]byte) (*domain.JourneyState, error),
+) ([]*domain.JourneyState, domain.ScanStats, error) {
+	var journeys []*domain.JourneyState
+	stats := domain.ScanStats{NodesVisited: 1}
 	var cursor uint64
 
+	expired := func() bool {
+		return !deadline.IsZero() && !time.Now().Before(deadline)
+	}
+
 	for {
-		keys, nextCursor, err := s.client.Native().Scan(ctx, cursor, pattern, s.scanCount).Result()
+		keys, nextCursor, err := node.Scan(ctx, cursor, pattern, s.scanCount).Result()
 		if err != nil {
-			return nil, fmt.Errorf("scan redis keys: %w", err)
+			if expired() {
+				stats.Truncated = true
+				return journeys, stats, nil
+			}
+			return nil, stats, fmt.Errorf("scan redis keys: %w", err)
 		}
+		stats.Iterations++
+		stats.KeysMatched += len(keys)
 
 		for _, key := range keys {
-			data, err := s.client.Get(ctx, key)
+			if expired() {
+				stats.Truncated = true
+				return journeys, stats, nil
+			}
+
+			data, err := node.Get(ctx, key).Result()
 			if err != nil {
 				s.logger.Warn("failed to get key", "key", key, "error", err)
+				stats.KeysFailed++
 				continue
 			}
 
-			var journey domain.JourneyState
-			if err := json.Unmarshal([]byte(data), &journey); err != nil {
+			journey, err := decode([]byte(data))
+			if err != nil {
 				s.logger.Warn("failed to unmarshal journey state", "key", key, "error", err)
+				stats.KeysFailed++
 				continue
 			}
 
-			journeys = append(journeys, &journey)
+			journey.Upgrade()
+			journeys = append(journeys, journey)
+			stats.KeysFetched++
 		}
 
 		cursor = nextCursor
 		if cursor == 0 {
 			break
 		}
+		if expired() {
+			stats.Truncated = true
+			break
+		}
 	}
 
-	s.logger.Debug("scan completed", "pattern", pattern, "count", len(journeys))
-	return journeys, nil
+	return journeys, stats, nil
 }
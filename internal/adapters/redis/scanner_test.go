@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pagedNode serves SCAN pages and key values from memory. Reading a key
+// whose name contains "slow" takes slowRead, as does a failing SCAN.
+type pagedNode struct {
+	pages    [][]string
+	values   map[string]string
+	scanErr  error
+	slowRead time.Duration
+}
+
+func (n *pagedNode) Scan(_ context.Context, cursor uint64, _ string, _ int64) *redis.ScanCmd {
+	if n.scanErr != nil && cursor > 0 {
+		time.Sleep(n.slowRead)
+		return redis.NewScanCmdResult(nil, 0, n.scanErr)
+	}
+	next := cursor + 1
+	if int(next) >= len(n.pages) {
+		next = 0
+	}
+	return redis.NewScanCmdResult(n.pages[cursor], next, nil)
+}
+
+func (n *pagedNode) Get(_ context.Context, key string) *redis.StringCmd {
+	if strings.Contains(key, "slow") {
+		time.Sleep(n.slowRead)
+	}
+	value, ok := n.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func journeyJSON(customerNumber string) string {
+	return `{"schema_version":1,"journey_id":"checkout","step":"cart","customer_number":"` + customerNumber + `"}`
+}
+
+func TestScanNodeDeadline(t *testing.T) {
+	values := map[string]string{
+		"journey:checkout:1":    journeyJSON("1"),
+		"journey:checkout:2":    journeyJSON("2"),
+		"journey:checkout:slow": journeyJSON("slow"),
+		"journey:checkout:3":    journeyJSON("3"),
+		"journey:checkout:bad":  "{",
+	}
+
+	tests := []struct {
+		name          string
+		pages         [][]string
+		scanErr       error
+		deadline      time.Duration // from now; zero means none
+		wantFetched   int
+		wantFailed    int
+		wantTruncated bool
+		wantErr       bool
+	}{
+		{
+			name:        "no deadline",
+			pages:       [][]string{{"journey:checkout:1", "journey:checkout:slow"}, {"journey:checkout:2", "journey:checkout:bad"}},
+			wantFetched: 3,
+			wantFailed:  1,
+		},
+		{
+			name:        "deadline not reached",
+			pages:       [][]string{{"journey:checkout:1", "journey:checkout:2"}, {"journey:checkout:3"}},
+			deadline:    time.Hour,
+			wantFetched: 3,
+		},
+		{
+			name:          "deadline passes within a page",
+			pages:         [][]string{{"journey:checkout:1", "journey:checkout:slow", "journey:checkout:2"}, {"journey:checkout:3"}},
+			deadline:      20 * time.Millisecond,
+			wantFetched:   2,
+			wantTruncated: true,
+		},
+		{
+			name:          "deadline passes between pages",
+			pages:         [][]string{{"journey:checkout:1", "journey:checkout:slow"}, {"journey:checkout:2", "journey:checkout:3"}},
+			deadline:      20 * time.Millisecond,
+			wantFetched:   2,
+			wantTruncated: true,
+		},
+		{
+			name:          "scan fails after the deadline",
+			pages:         [][]string{{"journey:checkout:1"}, {"journey:checkout:2"}},
+			scanErr:       context.DeadlineExceeded,
+			deadline:      20 * time.Millisecond,
+			wantFetched:   1,
+			wantTruncated: true,
+		},
+		{
+			name:     "scan fails before the deadline",
+			pages:    [][]string{{"journey:checkout:1"}, {"journey:checkout:2"}},
+			scanErr:  errors.New("connection reset"),
+			deadline: time.Hour,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scanner{scanCount: 100, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			node := &pagedNode{pages: tt.pages, values: values, scanErr: tt.scanErr, slowRead: 40 * time.Millisecond}
+
+			var deadline time.Time
+			if tt.deadline > 0 {
+				deadline = time.Now().Add(tt.deadline)
+			}
+
+			journeys, stats, err := s.scanNode(context.Background(), node, "journey:checkout:*", deadline, decodeFull)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("scanNode: %v", err)
+			}
+			if len(journeys) != tt.wantFetched || stats.KeysFetched != tt.wantFetched {
+				t.Errorf("fetched %d journeys (stats %d), want %d", len(journeys), stats.KeysFetched, tt.wantFetched)
+			}
+			if stats.KeysFailed != tt.wantFailed {
+				t.Errorf("failed keys = %d, want %d", stats.KeysFailed, tt.wantFailed)
+			}
+			if stats.Truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", stats.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// spendTTL keeps daily spend totals past the end of their day.
+const spendTTL = 48 * time.Hour
+
+// SpendLedger implements ports.SpendLedger using Redis.
+type SpendLedger struct {
+	client *Client
+	keys   KeyBuilder
+}
+
+// NewSpendLedger creates a new Redis spend ledger.
+func NewSpendLedger(client *Client, keys KeyBuilder) *SpendLedger {
+	return &SpendLedger{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// DailySpend returns today's accumulated spend.
+func (l *SpendLedger) DailySpend(ctx context.Context) (float64, error) {
+	data, err := l.client.Get(ctx, l.keys.SpendKey(time.Now()))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get daily spend: %w", err)
+	}
+
+	spend, err := strconv.ParseFloat(data, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse daily spend: %w", err)
+	}
+	return spend, nil
+}
+
+// AddSpend adds to today's spend and returns the new total.
+func (l *SpendLedger) AddSpend(ctx context.Context, amount float64) (float64, error) {
+	total, err := l.client.IncrByFloat(ctx, l.keys.SpendKey(time.Now()), amount, spendTTL)
+	if err != nil {
+		return 0, fmt.Errorf("add spend: %w", err)
+	}
+	return total, nil
+}
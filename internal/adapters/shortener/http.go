@@ -0,0 +1,106 @@
+package shortener
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"worker-project/internal/config"
+)
+
+// HTTPShortener implements ports.URLShortener against an HTTP shortener API.
+// It requests POST {endpoint} with a JSON body of the form {"url": "..."}
+// and expects {"short_url": "..."} in response.
+// Short URLs never change for a given URL, so results are cached for the
+// lifetime of the shortener.
+type HTTPShortener struct {
+	httpClient *http.Client
+	endpoint   string
+	logger     *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+type shortenRequest struct {
+	URL string `json:"url"`
+}
+
+type shortenResponse struct {
+	ShortURL string `json:"short_url"`
+}
+
+// NewHTTPShortener creates a new HTTP-backed URL shortener.
+func NewHTTPShortener(cfg config.ShortenerSettings, logger *slog.Logger) *HTTPShortener {
+	return &HTTPShortener{
+		httpClient: &http.Client{
+			Timeout: 2 * time.Second,
+		},
+		endpoint: cfg.Endpoint,
+		logger:   logger,
+		cache:    make(map[string]string),
+	}
+}
+
+// Shorten returns a short URL redirecting to the given URL.
+func (s *HTTPShortener) Shorten(ctx context.Context, url string) (string, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[url]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	short, err := s.fetch(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.cache[url] = short
+	s.mu.Unlock()
+
+	return short, nil
+}
+
+func (s *HTTPShortener) fetch(ctx context.Context, url string) (string, error) {
+	payload, err := json.Marshal(shortenRequest{URL: url})
+	if err != nil {
+		return "", fmt.Errorf("encode shorten request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build shorten request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("shorten url: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.logger.Warn("failed to close response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("shorten url: status %d", resp.StatusCode)
+	}
+
+	var body shortenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode shorten response: %w", err)
+	}
+	if body.ShortURL == "" {
+		return "", fmt.Errorf("shorten url: empty short_url in response")
+	}
+
+	return body.ShortURL, nil
+}
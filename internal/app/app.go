@@ -2,31 +2,67 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"worker-project/internal/config"
 	"worker-project/internal/domain"
+	"worker-project/internal/metrics"
 	"worker-project/internal/ports"
 	"worker-project/internal/service"
 )
 
 // Stats holds processing statistics.
 type Stats struct {
-	JourneyTypes int
+	JourneyTypes  int
 	TotalSessions int
-	Processed    int
-	Errors       int
+	Processed     int
+	Skipped       int
+	Errors        int
+	DeadLettered  int
+	MessagesSent  int
+	QuotaSkipped  int
+	SpendCapped   int
+
+	// CustomerCapped counts sends skipped by the customer's cross-journey daily cap.
+	CustomerCapped int
+
+	// DeadlineSkipped counts sessions not started because the run deadline was near.
+	DeadlineSkipped int
+}
+
+func (s *Stats) add(other Stats) {
+	s.TotalSessions += other.TotalSessions
+	s.Processed += other.Processed
+	s.Skipped += other.Skipped
+	s.Errors += other.Errors
+	s.DeadLettered += other.DeadLettered
+	s.MessagesSent += other.MessagesSent
+	s.QuotaSkipped += other.QuotaSkipped
+	s.SpendCapped += other.SpendCapped
+	s.CustomerCapped += other.CustomerCapped
+	s.DeadlineSkipped += other.DeadlineSkipped
 }
 
 // App is the main application container.
 type App struct {
-	cfg          *config.AppConfig
-	logger       *slog.Logger
-	scanner      ports.JourneyScanner
-	repository   ports.StateRepository
-	configLoader ports.JourneyConfigLoader
-	messenger    ports.Messenger
-	processor    *service.Processor
+	cfg            *config.AppConfig
+	logger         *slog.Logger
+	scanner        ports.JourneyScanner
+	repository     ports.StateRepository
+	configLoader   ports.JourneyConfigLoader
+	templates      ports.TemplateRenderer
+	messenger      *countingMessenger
+	metrics        *metrics.EMFEmitter
+	healthChecks   []HealthCheck
+	scanBaseline   ports.ScanBaseline
+	runTracker     ports.RunTracker
+	journeyTracker ports.JourneyTracker
+	processor      *service.Processor
+	budget         *errorBudget
+	pace           time.Duration
 }
 
 // Options configures the App.
@@ -37,41 +73,100 @@ type Options struct {
 	Repository   ports.StateRepository
 	ConfigLoader ports.JourneyConfigLoader
 	Messenger    ports.Messenger
+	KillSwitch   ports.KillSwitch
+	FeatureFlags ports.FeatureFlagEvaluator
+	Events       ports.EventPublisher
+	Decisions    ports.DecisionLog
+
+	// CustomerLocker, when set, serializes processing of each customer.
+	CustomerLocker ports.CustomerLocker
+
+	// Metrics, when set, receives run metrics in CloudWatch EMF.
+	Metrics *metrics.EMFEmitter
+
+	// Templates, when set, is used to warm template caches before processing.
+	Templates ports.TemplateRenderer
+
+	// HealthChecks run before scanning; any failure aborts the run.
+	HealthChecks []HealthCheck
+
+	// ScanBaseline, when set, is used to alert on anomalous drops in the
+	// number of scanned journeys.
+	ScanBaseline ports.ScanBaseline
+
+	// RunTracker, when set, records successful runs and enables catch-up
+	// pacing after downtime.
+	RunTracker ports.RunTracker
+
+	// JourneyTracker, when set, records when each journey was first seen
+	// and enables burn_in observe-only windows.
+	JourneyTracker ports.JourneyTracker
 }
 
 // New creates a new App with all dependencies injected.
 func New(opts Options) *App {
+	messenger := &countingMessenger{Messenger: opts.Messenger}
+
 	processor := service.NewProcessor(
 		opts.Repository,
-		opts.Messenger,
+		messenger,
+		opts.KillSwitch,
+		opts.FeatureFlags,
+		opts.Events,
+		opts.Decisions,
+		opts.CustomerLocker,
+		service.ProcessorConfig{
+			TestCustomers:    opts.Config.Worker.TestCustomers,
+			TenantQuotas:     opts.Config.Worker.TenantQuotas,
+			CustomerDailyCap: opts.Config.Worker.CustomerDailyCap,
+			CustomerLockWait: opts.Config.Worker.CustomerLockWait,
+			MaxRunJitter:     opts.Config.Worker.MaxRunJitter,
+			DeadlineMargin:   opts.Config.Worker.DeadlineMargin,
+		},
 		opts.Logger.With("component", "processor"),
 	)
 
 	return &App{
-		cfg:          opts.Config,
-		logger:       opts.Logger,
-		scanner:      opts.Scanner,
-		repository:   opts.Repository,
-		configLoader: opts.ConfigLoader,
-		messenger:    opts.Messenger,
-		processor:    processor,
+		cfg:            opts.Config,
+		logger:         opts.Logger,
+		scanner:        opts.Scanner,
+		repository:     opts.Repository,
+		configLoader:   opts.ConfigLoader,
+		templates:      opts.Templates,
+		messenger:      messenger,
+		metrics:        opts.Metrics,
+		healthChecks:   opts.HealthChecks,
+		scanBaseline:   opts.ScanBaseline,
+		runTracker:     opts.RunTracker,
+		journeyTracker: opts.JourneyTracker,
+		processor:      processor,
 	}
 }
 
 // Run executes the worker.
 func (a *App) Run(ctx context.Context) error {
 	a.logger.Info("starting worker")
+	startedAt := time.Now()
 
-	journeys, err := a.scanner.ScanAllJourneys(ctx)
+	if err := a.checkHealth(ctx); err != nil {
+		a.logger.Error("aborting run", "error", err)
+		return err
+	}
+
+	journeys, scanStats, err := a.scan(ctx)
 	if err != nil {
-		return &domain.JourneyError{
-			Op:  "ScanAllJourneys",
-			Err: err,
-		}
+		return err
+	}
+
+	// A truncated scan says nothing about the real journey count, so it is
+	// neither checked against nor recorded in the baseline.
+	if !scanStats.Truncated {
+		a.checkScanCount(ctx, len(journeys))
 	}
 
 	if len(journeys) == 0 {
 		a.logger.Info("no active journeys found")
+		a.recordRun(ctx)
 		return nil
 	}
 
@@ -82,65 +177,246 @@ func (a *App) Run(ctx context.Context) error {
 		"total_sessions", len(journeys),
 	)
 
-	stats := a.processJourneyGroups(ctx, grouped)
+	journeyIDs := orderJourneyIDs(grouped, a.cfg.Worker)
+
+	if a.cfg.Worker.TenantFairness {
+		for journeyID, states := range grouped {
+			grouped[journeyID] = interleaveByTenant(states)
+		}
+	}
+
+	if a.cfg.Worker.WarmCaches {
+		a.warmCaches(ctx, journeyIDs)
+	}
+
+	a.budget = newErrorBudget(a.cfg.Worker)
+	a.pace = a.catchUpPace(ctx)
+	stats, abortErr := a.processJourneyGroups(ctx, journeyIDs, grouped)
 
 	a.logger.Info("worker completed",
 		"journey_types", stats.JourneyTypes,
 		"total_sessions", stats.TotalSessions,
 		"processed", stats.Processed,
+		"skipped", stats.Skipped,
 		"errors", stats.Errors,
+		"dead_lettered", stats.DeadLettered,
+		"messages_sent", stats.MessagesSent,
+		"quota_skipped", stats.QuotaSkipped,
+		"spend_capped", stats.SpendCapped,
+		"customer_capped", stats.CustomerCapped,
+		"deadline_skipped", stats.DeadlineSkipped,
+		"scan_truncated", scanStats.Truncated,
 	)
 
+	a.emitRunMetrics(stats, time.Since(startedAt))
+
+	if abortErr != nil {
+		a.logger.Error("run aborted, error budget exhausted", "reason", abortErr)
+		return fmt.Errorf("%w: %w", domain.ErrRunAborted, abortErr)
+	}
+
+	a.recordRun(ctx)
 	return nil
 }
 
-func (a *App) processJourneyGroups(ctx context.Context, groups map[string][]*domain.JourneyState) Stats {
+// scan returns the active journeys, using the light scan when configured.
+func (a *App) scan(ctx context.Context) ([]*domain.JourneyState, domain.ScanStats, error) {
+	if a.cfg.Worker.LightScan {
+		journeys, stats, err := a.scanner.ScanAllJourneysLight(ctx)
+		if err != nil {
+			return nil, stats, &domain.JourneyError{Op: "ScanAllJourneysLight", Err: err}
+		}
+		a.logScanStats(stats)
+		return journeys, stats, nil
+	}
+
+	journeys, stats, err := a.scanner.ScanAllJourneys(ctx)
+	if err != nil {
+		return nil, stats, &domain.JourneyError{Op: "ScanAllJourneys", Err: err}
+	}
+	a.logScanStats(stats)
+	return journeys, stats, nil
+}
+
+// logScanStats logs how much of the keyspace the scan covered.
+func (a *App) logScanStats(stats domain.ScanStats) {
+	a.logger.Info("scan completed",
+		"iterations", stats.Iterations,
+		"keys_matched", stats.KeysMatched,
+		"keys_fetched", stats.KeysFetched,
+		"keys_failed", stats.KeysFailed,
+		"nodes_visited", stats.NodesVisited,
+		"truncated", stats.Truncated,
+	)
+}
+
+// processJourneyGroups processes journey groups in the given order, stopping
+// early when the context is cancelled, the deadline is near or the error
+// budget is exhausted. The returned error is non-nil only in the latter case.
+func (a *App) processJourneyGroups(ctx context.Context, journeyIDs []string, groups map[string][]*domain.JourneyState) (Stats, error) {
 	stats := Stats{
 		JourneyTypes: len(groups),
 	}
 
-	for journeyID, states := range groups {
-		stats.TotalSessions += len(states)
+	for i, journeyID := range journeyIDs {
+		if a.nearDeadline(ctx) {
+			for _, remaining := range journeyIDs[i:] {
+				stats.TotalSessions += len(groups[remaining])
+				stats.DeadlineSkipped += len(groups[remaining])
+			}
+			a.logger.Warn("deadline near, stopping processing", "skipped_sessions", stats.DeadlineSkipped)
+			return stats, nil
+		}
 
-		logger := a.logger.With("journey_id", journeyID, "session_count", len(states))
-		logger.Info("processing journey type")
+		groupStats := a.processJourneyGroup(ctx, journeyID, groups[journeyID])
+		stats.add(groupStats)
+		a.emitJourneyMetrics(journeyID, groupStats)
 
-		cfg, err := a.configLoader.LoadJourneyConfig(journeyID)
-		if err != nil {
-			logger.Error("failed to load config", "error", err)
-			stats.Errors += len(states)
-			continue
+		if err := a.budget.exceeded(); err != nil {
+			return stats, err
+		}
+
+		if ctx.Err() != nil {
+			a.logger.Warn("context cancelled, stopping processing")
+			return stats, nil
+		}
+	}
+
+	return stats, nil
+}
+
+func (a *App) processJourneyGroup(ctx context.Context, journeyID string, states []*domain.JourneyState) Stats {
+	stats := Stats{
+		TotalSessions: len(states),
+	}
+
+	logger := a.logger.With("journey_id", journeyID, "session_count", len(states))
+	logger.Info("processing journey type")
+
+	cfg, err := a.configLoader.LoadJourneyConfig(ctx, journeyID)
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		stats.Errors += len(states)
+		for range states {
+			a.budget.record(true)
 		}
+		return stats
+	}
+
+	if !cfg.Settings.IsEnabled() {
+		logger.Info("journey disabled, skipping")
+		stats.Skipped += len(states)
+		return stats
+	}
 
-		logger.Debug("loaded config",
-			"journey_name", cfg.Journey.Name,
-			"max_inactive_minutes", cfg.Settings.MaxInactiveTime.Minutes,
-			"lifecycle_repiques", len(cfg.Settings.LifecycleRepiques),
-			"steps", len(cfg.Steps),
-		)
-
-		for _, state := range states {
-			select {
-			case <-ctx.Done():
-				a.logger.Warn("context cancelled, stopping processing")
-				return stats
-			default:
-				if err := a.processor.ProcessJourney(ctx, cfg, state); err != nil {
-					a.logger.Error("failed to process customer",
-						"customer_number", state.CustomerNumber,
-						"error", err,
-					)
-					stats.Errors++
-				} else {
-					stats.Processed++
-				}
+	logger.Debug("loaded config",
+		"journey_name", cfg.Journey.Name,
+		"max_inactive_minutes", cfg.Settings.MaxInactiveTime.Minutes,
+		"lifecycle_repiques", len(cfg.Settings.LifecycleRepiques),
+		"steps", len(cfg.Steps),
+	)
+
+	process := a.processor.ProcessJourney
+	if until, observing := a.observeUntil(ctx, journeyID, cfg, logger); observing {
+		logger.Info("journey in observe-only burn-in, not sending", "observe_until", until)
+		process = a.processor.ObserveJourney
+	}
+
+	sentBefore := a.messenger.sent
+	quotaSkippedBefore := a.processor.QuotaSkipped()
+	customerCappedBefore := a.processor.CustomerCapSkipped()
+	cappedBefore := a.messenger.capped
+	steps := make(stepStats)
+
+	for i, state := range states {
+		if ctx.Err() != nil || a.budget.exceeded() != nil {
+			break
+		}
+		if a.nearDeadline(ctx) {
+			stats.DeadlineSkipped = len(states) - i
+			break
+		}
+
+		step := steps.step(state.Step)
+		step.StatesSeen++
+		attemptedBefore, stepSentBefore := a.messenger.attempted, a.messenger.sent
+
+		err := process(ctx, cfg, state)
+
+		step.RepiquesTriggered += a.messenger.attempted - attemptedBefore
+		step.MessagesSent += a.messenger.sent - stepSentBefore
+
+		if a.pace > 0 && a.messenger.sent > stepSentBefore {
+			pause(ctx, a.pace)
+		}
+
+		if err != nil {
+			a.logger.Error("failed to process customer",
+				"customer_number", state.CustomerNumber,
+				"error", err,
+			)
+			stats.Errors++
+			a.budget.record(true)
+			if a.recordFailure(ctx, state, err, logger) {
+				stats.DeadLettered++
 			}
+		} else {
+			stats.Processed++
+			a.budget.record(false)
+			a.resetFailures(ctx, state, logger)
 		}
 	}
 
+	stats.MessagesSent = a.messenger.sent - sentBefore
+	stats.QuotaSkipped = a.processor.QuotaSkipped() - quotaSkippedBefore
+	stats.SpendCapped = a.messenger.capped - cappedBefore
+	stats.CustomerCapped = a.processor.CustomerCapSkipped() - customerCappedBefore
+	a.reportStepStats(journeyID, steps)
 	return stats
 }
 
+// recordFailure counts a failure caused by the customer's own record and
+// dead-letters the journey once the failure threshold is reached. Cancelled
+// runs and infrastructure errors would fail any customer, so they are not
+// counted. It reports whether the journey was dead-lettered.
+func (a *App) recordFailure(ctx context.Context, state *domain.JourneyState, err error, logger *slog.Logger) bool {
+	threshold := a.cfg.Worker.DeadLetterThreshold
+	if threshold == 0 || ctx.Err() != nil || !errors.Is(err, domain.ErrRenderFailed) {
+		return false
+	}
+
+	failures, err := a.repository.IncrementFailureCount(ctx, state.JourneyID, state.CustomerNumber)
+	if err != nil {
+		logger.Warn("failed to record processing failure", "customer_number", state.CustomerNumber, "error", err)
+		return false
+	}
+	if failures < threshold {
+		return false
+	}
+
+	if err := a.repository.DeadLetterJourney(ctx, state.JourneyID, state.CustomerNumber); err != nil {
+		logger.Error("failed to dead-letter journey", "customer_number", state.CustomerNumber, "error", err)
+		return false
+	}
+
+	logger.Error("journey dead-lettered",
+		"customer_number", state.CustomerNumber,
+		"consecutive_failures", failures,
+	)
+	return true
+}
+
+// resetFailures clears the consecutive failure count after a successful run.
+func (a *App) resetFailures(ctx context.Context, state *domain.JourneyState, logger *slog.Logger) {
+	if a.cfg.Worker.DeadLetterThreshold == 0 {
+		return
+	}
+
+	if err := a.repository.ResetFailureCount(ctx, state.JourneyID, state.CustomerNumber); err != nil {
+		logger.Warn("failed to reset failure count", "customer_number", state.CustomerNumber, "error", err)
+	}
+}
+
 func groupByJourneyID(journeys []*domain.JourneyState) map[string][]*domain.JourneyState {
 	groups := make(map[string][]*domain.JourneyState)
 	for _, j := range journeys {
@@ -148,3 +424,24 @@ func groupByJourneyID(journeys []*domain.JourneyState) map[string][]*domain.Jour
 	}
 	return groups
 }
+
+// countingMessenger counts attempted and successful sends of the wrapped
+// messenger, and sends refused by the spend cap.
+type countingMessenger struct {
+	ports.Messenger
+	attempted int
+	sent      int
+	capped    int
+}
+
+func (m *countingMessenger) Send(ctx context.Context, msg domain.Message) error {
+	m.attempted++
+	if err := m.Messenger.Send(ctx, msg); err != nil {
+		if errors.Is(err, domain.ErrSpendCapReached) {
+			m.capped++
+		}
+		return err
+	}
+	m.sent++
+	return nil
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+)
+
+// memoryRepository is an in-memory ports.StateRepository tracking failure
+// counts and dead-lettered journeys.
+type memoryRepository struct {
+	failures     map[string]int
+	deadLettered map[string]bool
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{failures: make(map[string]int), deadLettered: make(map[string]bool)}
+}
+
+func (r *memoryRepository) GetJourneyState(context.Context, string, string) (*domain.JourneyState, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryRepository) GetRepiqueAttempts(context.Context, string, string) (*domain.RepiqueAttempts, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryRepository) IncrementRepiqueAttempt(context.Context, string, string, string) error {
+	return nil
+}
+
+func (r *memoryRepository) DeleteJourneyState(context.Context, string, string) error {
+	return nil
+}
+
+func (r *memoryRepository) IncrementFailureCount(_ context.Context, journeyID, customerNumber string) (int, error) {
+	key := journeyID + ":" + customerNumber
+	r.failures[key]++
+	return r.failures[key], nil
+}
+
+func (r *memoryRepository) ResetFailureCount(_ context.Context, journeyID, customerNumber string) error {
+	delete(r.failures, journeyID+":"+customerNumber)
+	return nil
+}
+
+func (r *memoryRepository) IncrementTenantSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) GetTenantSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) IncrementCustomerSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) GetCustomerSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) DeadLetterJourney(_ context.Context, journeyID, customerNumber string) error {
+	key := journeyID + ":" + customerNumber
+	r.deadLettered[key] = true
+	delete(r.failures, key)
+	return nil
+}
+
+func newDeadLetterApp(threshold int) (*App, *memoryRepository) {
+	repo := newMemoryRepository()
+	cfg := &config.AppConfig{Worker: config.WorkerConfig{DeadLetterThreshold: threshold}}
+	return &App{cfg: cfg, repository: repo}, repo
+}
+
+func TestRecordFailureDeadLettersAtThreshold(t *testing.T) {
+	a, repo := newDeadLetterApp(3)
+	state := &domain.JourneyState{JourneyID: "checkout", CustomerNumber: "5511999990000"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	renderErr := fmt.Errorf("build message: %w", domain.ErrRenderFailed)
+
+	for i := 1; i < 3; i++ {
+		if a.recordFailure(context.Background(), state, renderErr, logger) {
+			t.Fatalf("dead-lettered after %d failures", i)
+		}
+		if got := repo.failures["checkout:5511999990000"]; got != i {
+			t.Fatalf("failure count = %d after %d failures", got, i)
+		}
+	}
+
+	if !a.recordFailure(context.Background(), state, renderErr, logger) {
+		t.Fatal("not dead-lettered at the threshold")
+	}
+	if !repo.deadLettered["checkout:5511999990000"] {
+		t.Fatal("journey not moved to the dead-letter store")
+	}
+}
+
+func TestResetFailuresOnSuccess(t *testing.T) {
+	a, repo := newDeadLetterApp(2)
+	state := &domain.JourneyState{JourneyID: "checkout", CustomerNumber: "5511999990000"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	renderErr := fmt.Errorf("build message: %w", domain.ErrRenderFailed)
+	ctx := context.Background()
+
+	a.recordFailure(ctx, state, renderErr, logger)
+	a.resetFailures(ctx, state, logger)
+	if a.recordFailure(ctx, state, renderErr, logger) {
+		t.Fatal("dead-lettered although the failures were not consecutive")
+	}
+	if got := repo.failures["checkout:5511999990000"]; got != 1 {
+		t.Fatalf("failure count = %d, want 1 after a reset", got)
+	}
+}
+
+func TestRecordFailureIgnoresOtherFailures(t *testing.T) {
+	state := &domain.JourneyState{JourneyID: "checkout", CustomerNumber: "5511999990000"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	renderErr := fmt.Errorf("build message: %w", domain.ErrRenderFailed)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		threshold int
+		ctx       context.Context
+		err       error
+	}{
+		{name: "dead-lettering disabled", threshold: 0, ctx: context.Background(), err: renderErr},
+		{name: "infrastructure error", threshold: 1, ctx: context.Background(), err: errors.New("redis: connection refused")},
+		{name: "cancelled run", threshold: 1, ctx: cancelled, err: renderErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, repo := newDeadLetterApp(tt.threshold)
+			if a.recordFailure(tt.ctx, state, tt.err, logger) {
+				t.Fatal("journey dead-lettered")
+			}
+			if len(repo.failures) != 0 {
+				t.Fatalf("failure counted: %v", repo.failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+
+	"worker-project/internal/metrics"
+)
+
+// scanBaselineMinRuns is the number of recorded runs needed before scan
+// counts are compared against the baseline.
+const scanBaselineMinRuns = 3
+
+// checkScanCount compares the scanned journey count against the average of
+// recent runs and raises an alert when it drops below the configured ratio,
+// which usually means the event-tracker stopped writing or Redis was flushed.
+// The count is then recorded for future runs. Baseline failures are logged
+// and never block the run.
+func (a *App) checkScanCount(ctx context.Context, count int) {
+	if a.scanBaseline == nil || a.cfg.Worker.ScanDropRatio == 0 {
+		return
+	}
+
+	recent, err := a.scanBaseline.RecentCounts(ctx)
+	if err != nil {
+		a.logger.Warn("failed to load scan baseline", "error", err)
+	} else if len(recent) >= scanBaselineMinRuns {
+		var total int
+		for _, n := range recent {
+			total += n
+		}
+		average := float64(total) / float64(len(recent))
+
+		if average > 0 && float64(count) < average*a.cfg.Worker.ScanDropRatio {
+			a.logger.Error("scanned journey count dropped below baseline",
+				"alert", "scan_count_anomaly",
+				"count", count,
+				"baseline_average", average,
+				"baseline_runs", len(recent),
+				"drop_ratio", a.cfg.Worker.ScanDropRatio,
+			)
+			a.emitScanAnomaly()
+		}
+	}
+
+	if err := a.scanBaseline.RecordCount(ctx, count); err != nil {
+		a.logger.Warn("failed to record scan baseline", "error", err)
+	}
+}
+
+// emitScanAnomaly emits the scan anomaly metric when a metrics emitter is configured.
+func (a *App) emitScanAnomaly() {
+	if a.metrics == nil {
+		return
+	}
+
+	err := a.metrics.Emit(nil, []metrics.Metric{
+		{Name: "ScanCountAnomaly", Unit: metrics.UnitCount, Value: 1},
+	})
+	if err != nil {
+		a.logger.Warn("failed to emit scan anomaly metric", "error", err)
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"worker-project/internal/config"
+	"worker-project/internal/metrics"
+)
+
+// memoryBaseline is an in-memory ports.ScanBaseline.
+type memoryBaseline struct {
+	counts   []int
+	err      error
+	recorded []int
+}
+
+func (b *memoryBaseline) RecentCounts(context.Context) ([]int, error) {
+	return b.counts, b.err
+}
+
+func (b *memoryBaseline) RecordCount(_ context.Context, count int) error {
+	b.recorded = append(b.recorded, count)
+	return nil
+}
+
+func TestCheckScanCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		ratio     float64
+		counts    []int
+		err       error
+		count     int
+		wantAlert bool
+	}{
+		{name: "disabled", counts: []int{1000, 1000, 1000}, count: 10},
+		{name: "too few runs", ratio: 0.5, counts: []int{1000, 1000}, count: 10},
+		{name: "within baseline", ratio: 0.5, counts: []int{1000, 900, 1100}, count: 600},
+		{name: "at the ratio", ratio: 0.5, counts: []int{1000, 1000, 1000}, count: 500},
+		{name: "dropped below baseline", ratio: 0.5, counts: []int{1000, 900, 1100}, count: 499, wantAlert: true},
+		{name: "empty baseline", ratio: 0.5, counts: []int{0, 0, 0}, count: 0},
+		{name: "baseline unavailable", ratio: 0.5, err: errors.New("redis: connection refused"), count: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metricsOut bytes.Buffer
+			baseline := &memoryBaseline{counts: tt.counts, err: tt.err}
+			a := &App{
+				cfg:          &config.AppConfig{Worker: config.WorkerConfig{ScanDropRatio: tt.ratio}},
+				logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+				metrics:      metrics.NewEMFEmitter(&metricsOut, "Test"),
+				scanBaseline: baseline,
+			}
+
+			a.checkScanCount(context.Background(), tt.count)
+
+			if alerted := strings.Contains(metricsOut.String(), "ScanCountAnomaly"); alerted != tt.wantAlert {
+				t.Errorf("alert raised = %v, want %v", alerted, tt.wantAlert)
+			}
+
+			wantRecorded := 1
+			if tt.ratio == 0 {
+				wantRecorded = 0
+			}
+			if len(baseline.recorded) != wantRecorded {
+				t.Errorf("recorded %v, want %d count(s)", baseline.recorded, wantRecorded)
+			}
+		})
+	}
+}
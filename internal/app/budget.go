@@ -0,0 +1,52 @@
+package app
+
+import (
+	"fmt"
+
+	"worker-project/internal/config"
+)
+
+// errorBudgetMinSamples is the number of journeys that must be processed
+// before the error ratio is checked, so a few early failures don't abort a run.
+const errorBudgetMinSamples = 50
+
+// errorBudget tracks journey failures across a run and reports when the run
+// should be aborted.
+type errorBudget struct {
+	maxErrors int
+	maxRatio  float64
+
+	processed int
+	errors    int
+}
+
+func newErrorBudget(cfg config.WorkerConfig) *errorBudget {
+	return &errorBudget{
+		maxErrors: cfg.MaxRunErrors,
+		maxRatio:  cfg.MaxErrorRatio,
+	}
+}
+
+// record counts the outcome of processing one journey.
+func (b *errorBudget) record(failed bool) {
+	b.processed++
+	if failed {
+		b.errors++
+	}
+}
+
+// exceeded returns a non-nil error describing why the budget is exhausted.
+func (b *errorBudget) exceeded() error {
+	if b.maxErrors > 0 && b.errors >= b.maxErrors {
+		return fmt.Errorf("%d errors reached the limit of %d", b.errors, b.maxErrors)
+	}
+
+	if b.maxRatio > 0 && b.processed >= errorBudgetMinSamples {
+		ratio := float64(b.errors) / float64(b.processed)
+		if ratio > b.maxRatio {
+			return fmt.Errorf("error ratio %.2f exceeded the limit of %.2f (%d/%d)", ratio, b.maxRatio, b.errors, b.processed)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+
+	"worker-project/internal/config"
+)
+
+func TestErrorBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.WorkerConfig
+		processed int
+		failed    int
+		want      bool
+	}{
+		{name: "disabled", processed: 100, failed: 100},
+		{name: "under max errors", cfg: config.WorkerConfig{MaxRunErrors: 10}, processed: 20, failed: 9},
+		{name: "at max errors", cfg: config.WorkerConfig{MaxRunErrors: 10}, processed: 20, failed: 10, want: true},
+		{name: "max errors needs no samples", cfg: config.WorkerConfig{MaxRunErrors: 2}, processed: 2, failed: 2, want: true},
+		{name: "ratio before min samples", cfg: config.WorkerConfig{MaxErrorRatio: 0.1}, processed: errorBudgetMinSamples - 1, failed: 40},
+		{name: "ratio at limit", cfg: config.WorkerConfig{MaxErrorRatio: 0.1}, processed: 100, failed: 10},
+		{name: "ratio over limit", cfg: config.WorkerConfig{MaxErrorRatio: 0.1}, processed: 100, failed: 11, want: true},
+		{name: "ratio over limit at min samples", cfg: config.WorkerConfig{MaxErrorRatio: 0.5}, processed: errorBudgetMinSamples, failed: 26, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newErrorBudget(tt.cfg)
+			for i := 0; i < tt.processed; i++ {
+				budget.record(i < tt.failed)
+			}
+
+			err := budget.exceeded()
+			if got := err != nil; got != tt.want {
+				t.Errorf("exceeded() = %v, want exceeded %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// catchUpPace returns the pause to take after each customer that was sent
+// messages. It is non-zero only when the gap since the last successful run
+// exceeds the catch-up threshold, so customers who crossed their thresholds
+// during downtime are not all messaged at once. Tracker failures are logged
+// and leave the run unpaced.
+func (a *App) catchUpPace(ctx context.Context) time.Duration {
+	if a.runTracker == nil || a.cfg.Worker.CatchUpGap == 0 {
+		return 0
+	}
+
+	lastRun, ok, err := a.runTracker.LastRunAt(ctx)
+	if err != nil {
+		a.logger.Warn("failed to load last run time", "error", err)
+		return 0
+	}
+	if !ok {
+		return 0
+	}
+
+	gap := time.Since(lastRun)
+	if gap <= a.cfg.Worker.CatchUpGap {
+		return 0
+	}
+
+	a.logger.Warn("catch-up mode enabled, pacing sends",
+		"last_run_at", lastRun,
+		"gap", gap,
+		"pace", a.cfg.Worker.CatchUpPace,
+	)
+	return a.cfg.Worker.CatchUpPace
+}
+
+// recordRun stores the completion time of a successful run.
+func (a *App) recordRun(ctx context.Context) {
+	if a.runTracker == nil {
+		return
+	}
+	if err := a.runTracker.RecordRun(ctx, time.Now()); err != nil {
+		a.logger.Warn("failed to record run time", "error", err)
+	}
+}
+
+// pause waits for d or until the context is cancelled.
+func pause(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"worker-project/internal/config"
+)
+
+// fixedRunTracker reports a fixed last run.
+type fixedRunTracker struct {
+	lastRun time.Time
+	ok      bool
+	err     error
+}
+
+func (t fixedRunTracker) LastRunAt(context.Context) (time.Time, bool, error) {
+	return t.lastRun, t.ok, t.err
+}
+
+func (t fixedRunTracker) RecordRun(context.Context, time.Time) error {
+	return nil
+}
+
+func TestCatchUpPace(t *testing.T) {
+	const pace = 250 * time.Millisecond
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		gap     time.Duration
+		tracker *fixedRunTracker
+		want    time.Duration
+	}{
+		{name: "no tracker", gap: time.Hour},
+		{name: "disabled", tracker: &fixedRunTracker{lastRun: now.Add(-24 * time.Hour), ok: true}},
+		{name: "no recorded run", gap: time.Hour, tracker: &fixedRunTracker{}},
+		{name: "tracker failure", gap: time.Hour, tracker: &fixedRunTracker{err: errors.New("redis: connection refused")}},
+		{name: "recent run", gap: time.Hour, tracker: &fixedRunTracker{lastRun: now.Add(-5 * time.Minute), ok: true}},
+		{name: "gap exceeded", gap: time.Hour, tracker: &fixedRunTracker{lastRun: now.Add(-2 * time.Hour), ok: true}, want: pace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{
+				cfg:    &config.AppConfig{Worker: config.WorkerConfig{CatchUpGap: tt.gap, CatchUpPace: pace}},
+				logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+			if tt.tracker != nil {
+				a.runTracker = tt.tracker
+			}
+
+			if got := a.catchUpPace(context.Background()); got != tt.want {
+				t.Errorf("catchUpPace() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
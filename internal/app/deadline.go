@@ -0,0 +1,13 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// nearDeadline reports whether less than the configured safety margin remains
+// before the context's deadline. Contexts without a deadline are never near.
+func (a *App) nearDeadline(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < a.cfg.Worker.DeadlineMargin
+}
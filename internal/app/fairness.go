@@ -0,0 +1,37 @@
+package app
+
+import (
+	"sort"
+
+	"worker-project/internal/domain"
+)
+
+// interleaveByTenant reorders states round-robin across tenants, so a run cut
+// short by its deadline or error budget has still reached customers of every
+// tenant instead of only those of the largest. Tenants take turns in
+// alphabetical order and each tenant's states keep their relative order.
+func interleaveByTenant(states []*domain.JourneyState) []*domain.JourneyState {
+	byTenant := make(map[string][]*domain.JourneyState)
+	for _, state := range states {
+		byTenant[state.TenantID] = append(byTenant[state.TenantID], state)
+	}
+	if len(byTenant) < 2 {
+		return states
+	}
+
+	tenants := make([]string, 0, len(byTenant))
+	for tenantID := range byTenant {
+		tenants = append(tenants, tenantID)
+	}
+	sort.Strings(tenants)
+
+	interleaved := make([]*domain.JourneyState, 0, len(states))
+	for round := 0; len(interleaved) < len(states); round++ {
+		for _, tenantID := range tenants {
+			if round < len(byTenant[tenantID]) {
+				interleaved = append(interleaved, byTenant[tenantID][round])
+			}
+		}
+	}
+	return interleaved
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HealthCheck verifies a dependency the worker needs before it starts scanning.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// checkHealth runs every health check and returns all failures joined.
+func (a *App) checkHealth(ctx context.Context) error {
+	var errs []error
+	for _, hc := range a.healthChecks {
+		if err := hc.Check(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hc.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("startup health check failed: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"time"
+
+	"worker-project/internal/metrics"
+)
+
+// emitJourneyMetrics emits per-journey counters when a metrics emitter is configured.
+func (a *App) emitJourneyMetrics(journeyID string, stats Stats) {
+	if a.metrics == nil {
+		return
+	}
+
+	err := a.metrics.Emit(map[string]string{"JourneyID": journeyID}, []metrics.Metric{
+		{Name: "Processed", Unit: metrics.UnitCount, Value: float64(stats.Processed)},
+		{Name: "Errors", Unit: metrics.UnitCount, Value: float64(stats.Errors)},
+		{Name: "DeadLettered", Unit: metrics.UnitCount, Value: float64(stats.DeadLettered)},
+		{Name: "MessagesSent", Unit: metrics.UnitCount, Value: float64(stats.MessagesSent)},
+		{Name: "TenantQuotaSkipped", Unit: metrics.UnitCount, Value: float64(stats.QuotaSkipped)},
+		{Name: "SpendCapSkipped", Unit: metrics.UnitCount, Value: float64(stats.SpendCapped)},
+		{Name: "CustomerCapSkipped", Unit: metrics.UnitCount, Value: float64(stats.CustomerCapped)},
+	})
+	if err != nil {
+		a.logger.Warn("failed to emit journey metrics", "journey_id", journeyID, "error", err)
+	}
+}
+
+// emitRunMetrics emits run totals and duration when a metrics emitter is configured.
+func (a *App) emitRunMetrics(stats Stats, duration time.Duration) {
+	if a.metrics == nil {
+		return
+	}
+
+	err := a.metrics.Emit(nil, []metrics.Metric{
+		{Name: "Processed", Unit: metrics.UnitCount, Value: float64(stats.Processed)},
+		{Name: "Errors", Unit: metrics.UnitCount, Value: float64(stats.Errors)},
+		{Name: "DeadLettered", Unit: metrics.UnitCount, Value: float64(stats.DeadLettered)},
+		{Name: "MessagesSent", Unit: metrics.UnitCount, Value: float64(stats.MessagesSent)},
+		{Name: "TenantQuotaSkipped", Unit: metrics.UnitCount, Value: float64(stats.QuotaSkipped)},
+		{Name: "SpendCapSkipped", Unit: metrics.UnitCount, Value: float64(stats.SpendCapped)},
+		{Name: "CustomerCapSkipped", Unit: metrics.UnitCount, Value: float64(stats.CustomerCapped)},
+		{Name: "Duration", Unit: metrics.UnitMilliseconds, Value: float64(duration.Milliseconds())},
+	})
+	if err != nil {
+		a.logger.Warn("failed to emit run metrics", "error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"worker-project/internal/config"
+)
+
+// observeUntil returns when a journey leaves its observe-only burn-in, and
+// whether it is still in it. A journey observes until both observe_until and
+// burn_in from first sight have passed. When the first-seen time cannot be
+// read the journey is observed, so a Redis blip never makes a new journey go
+// live early.
+func (a *App) observeUntil(ctx context.Context, journeyID string, cfg *config.JourneyConfig, logger *slog.Logger) (time.Time, bool) {
+	now := time.Now()
+
+	var until time.Time
+	if cfg.Settings.ObserveUntil != nil {
+		until = *cfg.Settings.ObserveUntil
+	}
+
+	if a.journeyTracker != nil {
+		firstSeen, err := a.journeyTracker.FirstSeen(ctx, journeyID, now)
+		if err != nil {
+			if burnIn := cfg.Settings.BurnIn.ToDuration(); burnIn > 0 {
+				logger.Warn("failed to read journey first seen, observing", "error", err)
+				return now.Add(burnIn), true
+			}
+			logger.Warn("failed to record journey first seen", "error", err)
+		} else if burnIn := firstSeen.Add(cfg.Settings.BurnIn.ToDuration()); burnIn.After(until) {
+			until = burnIn
+		}
+	}
+
+	return until, now.Before(until)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"sort"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+)
+
+// orderJourneyIDs returns the journey IDs of groups in the configured
+// processing order, so runs that cannot finish process journeys predictably.
+// Ties are broken alphabetically.
+func orderJourneyIDs(groups map[string][]*domain.JourneyState, cfg config.WorkerConfig) []string {
+	journeyIDs := make([]string, 0, len(groups))
+	for journeyID := range groups {
+		journeyIDs = append(journeyIDs, journeyID)
+	}
+	sort.Strings(journeyIDs)
+
+	switch cfg.ProcessingOrder {
+	case config.OrderLargestFirst:
+		sort.SliceStable(journeyIDs, func(i, j int) bool {
+			return len(groups[journeyIDs[i]]) > len(groups[journeyIDs[j]])
+		})
+	case config.OrderSmallestFirst:
+		sort.SliceStable(journeyIDs, func(i, j int) bool {
+			return len(groups[journeyIDs[i]]) < len(groups[journeyIDs[j]])
+		})
+	case config.OrderPriority:
+		// Listed journeys come first in list order; the rest follow alphabetically.
+		rank := make(map[string]int, len(cfg.JourneyPriority))
+		for i, journeyID := range cfg.JourneyPriority {
+			if _, ok := rank[journeyID]; !ok {
+				rank[journeyID] = i
+			}
+		}
+		sort.SliceStable(journeyIDs, func(i, j int) bool {
+			ri, iok := rank[journeyIDs[i]]
+			rj, jok := rank[journeyIDs[j]]
+			if iok && jok {
+				return ri < rj
+			}
+			return iok && !jok
+		})
+	}
+
+	return journeyIDs
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"sort"
+
+	"worker-project/internal/metrics"
+)
+
+// stepCounters holds the per-step analytics of a journey type for one run.
+// RepiquesTriggered counts triggered repiques that reached the messenger,
+// whether or not the send succeeded.
+type stepCounters struct {
+	StatesSeen        int
+	RepiquesTriggered int
+	MessagesSent      int
+}
+
+// stepStats aggregates step counters by step ID.
+type stepStats map[string]*stepCounters
+
+func (s stepStats) step(stepID string) *stepCounters {
+	c, ok := s[stepID]
+	if !ok {
+		c = &stepCounters{}
+		s[stepID] = c
+	}
+	return c
+}
+
+// reportStepStats logs per-step counters of a journey type and emits them
+// when a metrics emitter is configured.
+func (a *App) reportStepStats(journeyID string, steps stepStats) {
+	stepIDs := make([]string, 0, len(steps))
+	for stepID := range steps {
+		stepIDs = append(stepIDs, stepID)
+	}
+	sort.Strings(stepIDs)
+
+	for _, stepID := range stepIDs {
+		c := steps[stepID]
+		a.logger.Info("step analytics",
+			"journey_id", journeyID,
+			"step", stepID,
+			"states_seen", c.StatesSeen,
+			"repiques_triggered", c.RepiquesTriggered,
+			"messages_sent", c.MessagesSent,
+		)
+
+		if a.metrics == nil {
+			continue
+		}
+		err := a.metrics.Emit(map[string]string{"JourneyID": journeyID, "Step": stepID}, []metrics.Metric{
+			{Name: "StatesSeen", Unit: metrics.UnitCount, Value: float64(c.StatesSeen)},
+			{Name: "RepiquesTriggered", Unit: metrics.UnitCount, Value: float64(c.RepiquesTriggered)},
+			{Name: "MessagesSent", Unit: metrics.UnitCount, Value: float64(c.MessagesSent)},
+		})
+		if err != nil {
+			a.logger.Warn("failed to emit step metrics", "journey_id", journeyID, "step", stepID, "error", err)
+		}
+	}
+}
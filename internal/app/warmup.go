@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// warmCaches loads the configs and template configs of the scanned journeys
+// concurrently, so per-customer processing does not pay the fetch latency.
+// Failures are only logged; processing reports them again per journey.
+func (a *App) warmCaches(ctx context.Context, journeyIDs []string) {
+	var wg sync.WaitGroup
+
+	for _, journeyID := range journeyIDs {
+		wg.Add(1)
+		go func(journeyID string) {
+			defer wg.Done()
+
+			cfg, err := a.configLoader.LoadJourneyConfig(ctx, journeyID)
+			if err != nil {
+				a.logger.Warn("warm-up: failed to load config", "journey_id", journeyID, "error", err)
+				return
+			}
+
+			if a.templates == nil {
+				return
+			}
+
+			for _, ref := range cfg.TemplateRefs() {
+				if ctx.Err() != nil {
+					return
+				}
+				if _, err := a.templates.LoadTemplate(ctx, ref); err != nil {
+					a.logger.Warn("warm-up: failed to load template", "journey_id", journeyID, "template", ref, "error", err)
+				}
+			}
+		}(journeyID)
+	}
+
+	wg.Wait()
+	a.logger.Debug("caches warmed", "journeys", len(journeyIDs))
+}
@@ -1,20 +1,30 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // AppConfig holds application-level configuration.
 type AppConfig struct {
-	Redis     RedisConfig
-	AppConfig AppConfigSettings
-	Worker    WorkerConfig
+	Redis        RedisConfig
+	AppConfig    AppConfigSettings
+	Worker       WorkerConfig
+	FeatureFlags FeatureFlagSettings
+	Shortener    ShortenerSettings
+	Spend        SpendSettings
+	Signing      SigningSettings
 }
 
 // RedisConfig holds Redis connection settings.
 type RedisConfig struct {
 	Addr         string
+	ReadAddr     string // optional replica used for scans
+	KeyPrefix    string // optional namespace for all keys
+	ClusterMode  bool   // Addr holds comma-separated cluster seed nodes
 	Password     string
 	DB           int
 	DialTimeout  time.Duration
@@ -22,45 +32,289 @@ type RedisConfig struct {
 	WriteTimeout time.Duration
 	PoolSize     int
 	MinIdleConns int
+
+	// ConnectRetries is how many times a failed startup connection is
+	// retried, starting at ConnectBackoff and doubling.
+	ConnectRetries int
+	ConnectBackoff time.Duration
 }
 
 // AppConfigSettings holds AWS AppConfig settings.
 type AppConfigSettings struct {
-	Endpoint      string
-	ApplicationID string
-	EnvironmentID string
+	Endpoint         string
+	ApplicationID    string
+	EnvironmentID    string
+	RenderTimeout    time.Duration
+	MaxRenderedBytes int
+	InheritDefaults  bool // merge journey.default settings under each journey
+	RenderCache      bool // reuse renders within a run for matching metadata
+
+	// FetchTimeout bounds each profile fetch attempt; failed attempts with a
+	// connection error or 5xx are retried up to FetchRetries times, starting
+	// at FetchBackoff and doubling.
+	FetchTimeout time.Duration
+	FetchRetries int
+	FetchBackoff time.Duration
+
+	// CacheTTL is how long a loaded journey config is served before it is
+	// fetched again; a failed refetch keeps serving the last good config.
+	CacheTTL time.Duration
+}
+
+// FeatureFlagSettings holds feature flag service settings.
+// Flags are not evaluated when Endpoint is empty.
+type FeatureFlagSettings struct {
+	Endpoint string
+	CacheTTL time.Duration
+}
+
+// ShortenerSettings holds URL shortener settings.
+// Links are not shortened when Endpoint is empty.
+type ShortenerSettings struct {
+	Endpoint string
+}
+
+// SpendSettings caps message spend. Costs maps a template category to the
+// cost of one message; categories without a cost are free. A zero cap is
+// not enforced.
+type SpendSettings struct {
+	Costs    map[string]float64
+	RunCap   float64
+	DailyCap float64
+}
+
+// Enabled reports whether any spend cap applies.
+func (s SpendSettings) Enabled() bool {
+	return len(s.Costs) > 0 && (s.RunCap > 0 || s.DailyCap > 0)
+}
+
+// SigningSettings holds per-tenant keys used to append a verification code
+// to outgoing messages. Tenants without a key receive unsigned messages.
+type SigningSettings struct {
+	Keys map[string]string
 }
 
+// Journey processing orders.
+const (
+	OrderAlphabetical  = "alphabetical"
+	OrderLargestFirst  = "largest_first"
+	OrderSmallestFirst = "smallest_first"
+	OrderPriority      = "priority"
+)
+
 // WorkerConfig holds worker-specific settings.
 type WorkerConfig struct {
-	ScanCount       int64
-	DefaultStateTTL time.Duration
+	ScanCount          int64
+	ScanMaxDuration    time.Duration // scans stop with partial results after this; zero means no limit
+	DefaultStateTTL    time.Duration
+	KillSwitchCacheTTL time.Duration
+	LightScan          bool
+
+	// DeadLetterThreshold is the number of consecutive failures to render a
+	// customer's messages after which the journey is dead-lettered. A
+	// successful run resets the count. Zero disables dead-lettering.
+	DeadLetterThreshold int
+
+	// MaxRunErrors aborts a run once this many journeys have failed, and
+	// MaxErrorRatio once the share of failed journeys exceeds it. Zero disables
+	// either check.
+	MaxRunErrors  int
+	MaxErrorRatio float64
+
+	// ProcessingOrder is the order in which journey types are processed.
+	// With OrderPriority, JourneyPriority lists the journeys to process first.
+	ProcessingOrder string
+	JourneyPriority []string
+
+	// TenantFairness interleaves each journey's customers round-robin by
+	// tenant, so a large tenant cannot starve smaller ones when a run is cut
+	// short. Journeys are still processed one after another in
+	// ProcessingOrder.
+	TenantFairness bool
+
+	// CustomerLock serializes processing of a customer across workers.
+	// A worker waits up to CustomerLockWait for the lock before skipping the
+	// customer until the next run; customers are processed one at a time, so
+	// each wait stalls the whole run. Zero tries the lock once. Held locks are
+	// refreshed while processing, and CustomerLockTTL frees abandoned locks.
+	CustomerLock     bool
+	CustomerLockTTL  time.Duration
+	CustomerLockWait time.Duration
+
+	// ScanDropRatio raises an alert when a run scans fewer journeys than this
+	// fraction of the average of the last ScanBaselineRuns runs. Zero disables
+	// the check.
+	ScanDropRatio    float64
+	ScanBaselineRuns int
+
+	// DeadlineMargin stops the worker from starting new journeys once less
+	// than this much time remains before the context deadline (the Lambda
+	// timeout), so in-flight sends are not killed midway.
+	DeadlineMargin time.Duration
+
+	// MaxRunJitter bounds the total send_jitter delay of a run. Customers are
+	// processed one at a time, so every delay stalls the run; once the budget
+	// is spent, or the delay would run into DeadlineMargin, repiques are sent
+	// without delay. Zero disables send jitter.
+	MaxRunJitter time.Duration
+
+	// CatchUpGap enables catch-up mode when the last successful run is older
+	// than this; the worker then pauses CatchUpPace after each customer it
+	// messages. Zero disables catch-up mode.
+	CatchUpGap  time.Duration
+	CatchUpPace time.Duration
+
+	// PublishEvents publishes journey lifecycle events to a Redis stream.
+	PublishEvents bool
+
+	// DecisionLogPath, when set, records every repique evaluation as JSONL
+	// to this file ("-" for stdout). Customer numbers are hashed with
+	// DecisionLogSalt.
+	DecisionLogPath string
+	DecisionLogSalt string
+
+	// WarmCaches preloads journey and template configs concurrently before processing.
+	WarmCaches bool
+
+	// StartupCheckJourneys are journey IDs whose configs must load before a run starts.
+	StartupCheckJourneys []string
+
+	// TenantQuotas caps daily sends per tenant ID. Tenants without an entry
+	// are unlimited.
+	TenantQuotas map[string]int
+
+	// CustomerDailyCap caps the messages a customer receives per day across
+	// all journeys. Zero disables the cap.
+	CustomerDailyCap int
+
+	// TestCustomers bypass attempt caps. Only loaded when ALLOW_TEST_CUSTOMERS=true,
+	// which must never be set in production.
+	TestCustomers []string
+
+	// AllowStateAging enables the forceage command, which rewinds journey
+	// timestamps. Set by ALLOW_STATE_AGING=true, which must never be set in
+	// production.
+	AllowStateAging bool
 }
 
 // LoadFromEnv loads configuration from environment variables with sensible defaults.
 func LoadFromEnv() (*AppConfig, error) {
 	cfg := &AppConfig{
 		Redis: RedisConfig{
-			Addr:         getEnvOrDefault("REDIS_ADDR", "localhost:6379"),
-			Password:     os.Getenv("REDIS_PASSWORD"),
-			DB:           0,
-			DialTimeout:  5 * time.Second,
-			ReadTimeout:  3 * time.Second,
-			WriteTimeout: 3 * time.Second,
-			PoolSize:     10,
-			MinIdleConns: 2,
+			Addr:           getEnvOrDefault("REDIS_ADDR", "localhost:6379"),
+			ReadAddr:       os.Getenv("REDIS_READ_ADDR"),
+			KeyPrefix:      os.Getenv("REDIS_KEY_PREFIX"),
+			ClusterMode:    os.Getenv("REDIS_CLUSTER_MODE") == "true",
+			Password:       os.Getenv("REDIS_PASSWORD"),
+			DB:             0,
+			DialTimeout:    5 * time.Second,
+			ReadTimeout:    3 * time.Second,
+			WriteTimeout:   3 * time.Second,
+			PoolSize:       10,
+			MinIdleConns:   2,
+			ConnectRetries: 3,
+			ConnectBackoff: 500 * time.Millisecond,
 		},
 		AppConfig: AppConfigSettings{
-			Endpoint:      getEnvOrDefault("APPCONFIG_ENDPOINT", "http://localhost:2772"),
-			ApplicationID: os.Getenv("APPCONFIG_APP_ID"),
-			EnvironmentID: os.Getenv("APPCONFIG_ENV_ID"),
+			Endpoint:         getEnvOrDefault("APPCONFIG_ENDPOINT", "http://localhost:2772"),
+			ApplicationID:    os.Getenv("APPCONFIG_APP_ID"),
+			EnvironmentID:    os.Getenv("APPCONFIG_ENV_ID"),
+			RenderTimeout:    2 * time.Second,
+			MaxRenderedBytes: 64 * 1024,
+			InheritDefaults:  os.Getenv("APPCONFIG_INHERIT_DEFAULTS") == "true",
+			RenderCache:      os.Getenv("APPCONFIG_RENDER_CACHE") == "true",
+			FetchTimeout:     5 * time.Second,
+			FetchRetries:     2,
+			FetchBackoff:     200 * time.Millisecond,
+			CacheTTL:         time.Minute,
+		},
+		FeatureFlags: FeatureFlagSettings{
+			Endpoint: os.Getenv("FEATURE_FLAGS_ENDPOINT"),
+			CacheTTL: time.Minute,
+		},
+		Shortener: ShortenerSettings{
+			Endpoint: os.Getenv("URL_SHORTENER_ENDPOINT"),
 		},
 		Worker: WorkerConfig{
-			ScanCount:       100,
-			DefaultStateTTL: 24 * time.Hour,
+			ScanCount:          100,
+			DefaultStateTTL:    24 * time.Hour,
+			KillSwitchCacheTTL: 30 * time.Second,
+			LightScan:          os.Getenv("WORKER_LIGHT_SCAN") == "true",
+			WarmCaches:         os.Getenv("WORKER_WARM_CACHES") == "true",
+			DeadlineMargin:     30 * time.Second,
+			MaxRunJitter:       2 * time.Minute,
+			CatchUpPace:        250 * time.Millisecond,
+			ScanBaselineRuns:   24,
+			PublishEvents:      os.Getenv("WORKER_PUBLISH_EVENTS") == "true",
+			DecisionLogPath:    os.Getenv("DECISION_LOG_PATH"),
+			DecisionLogSalt:    os.Getenv("DECISION_LOG_SALT"),
+			CustomerLock:       os.Getenv("WORKER_CUSTOMER_LOCK") == "true",
+			CustomerLockTTL:    30 * time.Second,
+			ProcessingOrder:    getEnvOrDefault("WORKER_PROCESSING_ORDER", OrderAlphabetical),
+			TenantFairness:     os.Getenv("WORKER_TENANT_FAIRNESS") == "true",
+			AllowStateAging:    os.Getenv("ALLOW_STATE_AGING") == "true",
 		},
 	}
 
+	cfg.Worker.StartupCheckJourneys = getEnvList("STARTUP_CHECK_JOURNEYS")
+	cfg.Worker.JourneyPriority = getEnvList("WORKER_JOURNEY_PRIORITY")
+
+	quotas, err := parseTenantQuotas(getEnvList("TENANT_SEND_QUOTAS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Worker.TenantQuotas = quotas
+
+	if cfg.Worker.CustomerDailyCap, err = getEnvInt("WORKER_CUSTOMER_DAILY_CAP"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Worker.DeadLetterThreshold, err = getEnvInt("WORKER_DEAD_LETTER_THRESHOLD"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Worker.MaxRunErrors, err = getEnvInt("WORKER_MAX_RUN_ERRORS"); err != nil {
+		return nil, err
+	}
+	if cfg.Worker.MaxErrorRatio, err = getEnvFloat("WORKER_MAX_ERROR_RATIO"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Worker.CatchUpGap, err = getEnvDuration("WORKER_CATCH_UP_GAP"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Worker.ScanDropRatio, err = getEnvFloat("WORKER_SCAN_DROP_RATIO"); err != nil {
+		return nil, err
+	}
+
+	if cfg.Worker.ScanMaxDuration, err = getEnvDuration("WORKER_SCAN_MAX_DURATION"); err != nil {
+		return nil, err
+	}
+
+	costs, err := parseCosts(getEnvList("MESSAGE_CATEGORY_COSTS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Spend.Costs = costs
+
+	signingKeys, err := parseSigningKeys(getEnvList("MESSAGE_SIGNING_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Signing.Keys = signingKeys
+
+	if cfg.Spend.RunCap, err = getEnvFloat("SPEND_RUN_CAP"); err != nil {
+		return nil, err
+	}
+	if cfg.Spend.DailyCap, err = getEnvFloat("SPEND_DAILY_CAP"); err != nil {
+		return nil, err
+	}
+
+	if os.Getenv("ALLOW_TEST_CUSTOMERS") == "true" {
+		cfg.Worker.TestCustomers = getEnvList("TEST_CUSTOMER_NUMBERS")
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -74,3 +328,97 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseTenantQuotas parses "tenant=limit" entries into a quota map.
+func parseTenantQuotas(entries []string) (map[string]int, error) {
+	quotas := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		tenantID, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tenant quota %q (expected 'tenant=limit')", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenant quota %q: %w", entry, err)
+		}
+		quotas[strings.TrimSpace(tenantID)] = n
+	}
+	return quotas, nil
+}
+
+// parseCosts parses "category=cost" entries into a cost map.
+func parseCosts(entries []string) (map[string]float64, error) {
+	costs := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		category, cost, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid message cost %q (expected 'category=cost')", entry)
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(cost), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message cost %q: %w", entry, err)
+		}
+		costs[strings.TrimSpace(category)] = n
+	}
+	return costs, nil
+}
+
+// parseSigningKeys parses "tenant=key" entries into a signing key map.
+// Errors name the entry position rather than its content, which is secret.
+func parseSigningKeys(entries []string) (map[string]string, error) {
+	keys := make(map[string]string, len(entries))
+	for i, entry := range entries {
+		tenantID, key, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid signing key entry %d (expected 'tenant=key')", i+1)
+		}
+		keys[strings.TrimSpace(tenantID)] = strings.TrimSpace(key)
+	}
+	return keys, nil
+}
+
+func getEnvInt(key string) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func getEnvFloat(key string) (float64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func getEnvDuration(key string) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoadFromEnvTestCustomers(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow string
+		want  []string
+	}{
+		{name: "allowed", allow: "true", want: []string{"5511000000001", "5511000000002"}},
+		{name: "not allowed", allow: "", want: nil},
+		{name: "not explicitly true", allow: "1", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_CUSTOMER_NUMBERS", "5511000000001,5511000000002")
+			t.Setenv("ALLOW_TEST_CUSTOMERS", tt.allow)
+
+			cfg, err := LoadFromEnv()
+			if err != nil {
+				t.Fatalf("LoadFromEnv: %v", err)
+			}
+			if !slices.Equal(cfg.Worker.TestCustomers, tt.want) {
+				t.Errorf("test customers = %v, want %v", cfg.Worker.TestCustomers, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,16 @@ package config
 
 import "time"
 
+// CurrentJourneySchemaVersion is the journey config format this worker
+// understands. Configs without a schema_version are treated as this version.
+const CurrentJourneySchemaVersion = 1
+
 // JourneyConfig represents the configuration for a journey.
 type JourneyConfig struct {
-	Journey  Journey  `yaml:"journey"`
-	Settings Settings `yaml:"settings"`
-	Steps    []Step   `yaml:"steps"`
+	SchemaVersion int      `yaml:"schema_version,omitempty"`
+	Journey       Journey  `yaml:"journey"`
+	Settings      Settings `yaml:"settings"`
+	Steps         []Step   `yaml:"steps"`
 }
 
 // Journey holds journey identification.
@@ -17,9 +22,31 @@ type Journey struct {
 
 // Settings holds journey-level settings.
 type Settings struct {
+	Enabled           *bool           `yaml:"enabled,omitempty"`
 	MaxInactiveTime   Duration        `yaml:"max_inactive_time"`
 	Session           SessionSettings `yaml:"session"`
+	Consent           ConsentSettings `yaml:"consent"`
 	LifecycleRepiques []Repique       `yaml:"lifecycle_repiques"`
+
+	// MinRecoveryStep is the earliest step, in config order, at which
+	// recovery may fire. Customers in earlier steps are not messaged.
+	MinRecoveryStep string `yaml:"min_recovery_step,omitempty"`
+
+	// TerminalSteps are steps that complete the journey. Customers found in
+	// one are not messaged and their state is removed.
+	TerminalSteps []string `yaml:"terminal_steps,omitempty"`
+
+	// ObserveUntil and BurnIn keep the journey in observe-only mode, where
+	// decisions are evaluated and recorded but nothing is sent, until the
+	// given time or until BurnIn has elapsed since the worker first saw
+	// the journey. The journey goes live when both have passed.
+	ObserveUntil *time.Time `yaml:"observe_until,omitempty"`
+	BurnIn       Duration   `yaml:"burn_in,omitempty"`
+}
+
+// IsEnabled reports whether the journey is enabled. Defaults to true when unset.
+func (s Settings) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
 }
 
 // SessionSettings controls session behavior.
@@ -34,6 +61,13 @@ type ResetRepiques struct {
 	Step      bool `yaml:"step"`
 }
 
+// ConsentSettings controls consent expiry.
+// When MaxAge is zero, consent is not checked.
+type ConsentSettings struct {
+	MaxAge          Duration `yaml:"max_age"`
+	ReoptinTemplate string   `yaml:"reoptin_template,omitempty"`
+}
+
 // Duration represents a duration in minutes for YAML configuration.
 type Duration struct {
 	Minutes int `yaml:"minutes"`
@@ -53,11 +87,22 @@ type Step struct {
 
 // Repique represents a recovery message rule.
 type Repique struct {
-	ID          string    `yaml:"id"`
-	MaxAttempts int       `yaml:"max_attempts"`
-	Condition   Condition `yaml:"condition,omitempty"`
-	Trigger     Trigger   `yaml:"trigger,omitempty"`
-	Action      Action    `yaml:"action"`
+	ID          string       `yaml:"id"`
+	MaxAttempts int          `yaml:"max_attempts"`
+	Condition   Condition    `yaml:"condition,omitempty"`
+	Trigger     Trigger      `yaml:"trigger,omitempty"`
+	Action      Action       `yaml:"action"`
+	SendJitter  *JitterRange `yaml:"send_jitter,omitempty"`
+}
+
+// MaxSendJitter bounds a single send_jitter delay; the worker bounds their
+// total per run.
+const MaxSendJitter = time.Minute
+
+// JitterRange is a randomized delay applied before a repique is sent.
+type JitterRange struct {
+	MinSeconds int `yaml:"min_seconds"`
+	MaxSeconds int `yaml:"max_seconds"`
 }
 
 // Condition defines when a repique should trigger.
@@ -65,9 +110,17 @@ type Condition struct {
 	TimeInStep *TimeCondition `yaml:"time_in_step,omitempty"`
 }
 
+// Inactivity bases for time conditions.
+const (
+	BasisStepEntry       = "step_entry"       // time since the customer entered the step
+	BasisLastInteraction = "last_interaction" // time since the customer's last interaction
+)
+
 // TimeCondition defines a time-based condition.
+// Basis selects the reference time and defaults to BasisStepEntry.
 type TimeCondition struct {
-	GteMinutes int `yaml:"gte_minutes"`
+	GteMinutes int    `yaml:"gte_minutes"`
+	Basis      string `yaml:"basis,omitempty"`
 }
 
 // Trigger defines lifecycle-based triggers.
@@ -78,8 +131,19 @@ type Trigger struct {
 
 // Action defines what happens when a repique triggers.
 type Action struct {
-	Template   string `yaml:"template,omitempty"`
-	EndJourney bool   `yaml:"end_journey,omitempty"`
+	Template           string         `yaml:"template,omitempty"`
+	TemplatesByAttempt map[int]string `yaml:"templates_by_attempt,omitempty"`
+	FallbackTemplate   string         `yaml:"fallback_template,omitempty"`
+	EndJourney         bool           `yaml:"end_journey,omitempty"`
+}
+
+// TemplateForAttempt returns the template for a 1-based attempt number,
+// falling back to Template when the attempt has no specific template.
+func (a Action) TemplateForAttempt(attempt int) string {
+	if template, ok := a.TemplatesByAttempt[attempt]; ok {
+		return template
+	}
+	return a.Template
 }
 
 // FindStep finds a step by ID, returns nil if not found.
@@ -91,3 +155,66 @@ func (c *JourneyConfig) FindStep(stepID string) *Step {
 	}
 	return nil
 }
+
+// stepIndex returns the position of a step in the config, or -1 if absent.
+func (c *JourneyConfig) stepIndex(stepID string) int {
+	for i := range c.Steps {
+		if c.Steps[i].ID == stepID {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsRecoveryEligible reports whether a customer in the given step has
+// progressed far enough for recovery. Steps missing from the config are
+// treated as eligible.
+func (c *JourneyConfig) IsRecoveryEligible(stepID string) bool {
+	if c.Settings.MinRecoveryStep == "" {
+		return true
+	}
+	current := c.stepIndex(stepID)
+	return current < 0 || current >= c.stepIndex(c.Settings.MinRecoveryStep)
+}
+
+// IsTerminalStep reports whether the step completes the journey.
+func (c *JourneyConfig) IsTerminalStep(stepID string) bool {
+	for _, terminal := range c.Settings.TerminalSteps {
+		if terminal == stepID {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateRefs returns every template reference used by the journey, without duplicates.
+func (c *JourneyConfig) TemplateRefs() []string {
+	seen := make(map[string]bool)
+	var refs []string
+	add := func(ref string) {
+		if ref != "" && !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	addAction := func(a Action) {
+		add(a.Template)
+		add(a.FallbackTemplate)
+		for _, ref := range a.TemplatesByAttempt {
+			add(ref)
+		}
+	}
+
+	add(c.Settings.Consent.ReoptinTemplate)
+	for _, r := range c.Settings.LifecycleRepiques {
+		addAction(r.Action)
+	}
+	for _, step := range c.Steps {
+		for _, r := range step.Repiques {
+			addAction(r.Action)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestActionTemplateForAttempt(t *testing.T) {
+	action := Action{
+		Template: "journey.checkout.templates:default",
+		TemplatesByAttempt: map[int]string{
+			1: "journey.checkout.templates:soft",
+			2: "journey.checkout.templates:firm",
+			3: "journey.checkout.templates:discount",
+		},
+	}
+
+	tests := []struct {
+		attempt int
+		want    string
+	}{
+		{attempt: 1, want: "journey.checkout.templates:soft"},
+		{attempt: 3, want: "journey.checkout.templates:discount"},
+		{attempt: 4, want: "journey.checkout.templates:default"},
+	}
+
+	for _, tt := range tests {
+		if got := action.TemplateForAttempt(tt.attempt); got != tt.want {
+			t.Errorf("TemplateForAttempt(%d) = %q, want %q", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestActionTemplateForAttemptWithoutMapping(t *testing.T) {
+	action := Action{Template: "journey.checkout.templates:default"}
+
+	if got := action.TemplateForAttempt(2); got != action.Template {
+		t.Errorf("TemplateForAttempt(2) = %q, want %q", got, action.Template)
+	}
+}
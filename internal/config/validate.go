@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Validate validates the application configuration.
@@ -13,18 +14,126 @@ func (c *AppConfig) Validate() error {
 		errs = append(errs, errors.New("redis address is required"))
 	}
 
+	if c.Redis.ClusterMode && c.Redis.ReadAddr != "" {
+		errs = append(errs, errors.New("redis read address is not supported in cluster mode"))
+	}
+
 	if c.Redis.DialTimeout <= 0 {
 		errs = append(errs, errors.New("redis dial timeout must be positive"))
 	}
 
+	if c.Redis.ConnectRetries < 0 || c.Redis.ConnectBackoff < 0 {
+		errs = append(errs, errors.New("redis connect retries and backoff must not be negative"))
+	}
+
+	if c.AppConfig.RenderTimeout <= 0 {
+		errs = append(errs, errors.New("appconfig render timeout must be positive"))
+	}
+
+	if c.AppConfig.MaxRenderedBytes <= 0 {
+		errs = append(errs, errors.New("appconfig max rendered bytes must be positive"))
+	}
+
+	if c.AppConfig.FetchTimeout <= 0 {
+		errs = append(errs, errors.New("appconfig fetch timeout must be positive"))
+	}
+
+	if c.AppConfig.FetchRetries < 0 || c.AppConfig.FetchBackoff < 0 {
+		errs = append(errs, errors.New("appconfig fetch retries and backoff must not be negative"))
+	}
+
+	if c.AppConfig.CacheTTL <= 0 {
+		errs = append(errs, errors.New("appconfig cache TTL must be positive"))
+	}
+
 	if c.Worker.ScanCount <= 0 {
 		errs = append(errs, errors.New("worker scan count must be positive"))
 	}
 
+	if c.Worker.ScanMaxDuration < 0 {
+		errs = append(errs, errors.New("worker scan max duration must not be negative"))
+	}
+
 	if c.Worker.DefaultStateTTL <= 0 {
 		errs = append(errs, errors.New("worker default state TTL must be positive"))
 	}
 
+	if c.Worker.DeadLetterThreshold < 0 {
+		errs = append(errs, errors.New("worker dead-letter threshold must not be negative"))
+	}
+
+	if c.Worker.MaxRunErrors < 0 {
+		errs = append(errs, errors.New("worker max run errors must not be negative"))
+	}
+
+	if c.Worker.MaxErrorRatio < 0 || c.Worker.MaxErrorRatio > 1 {
+		errs = append(errs, errors.New("worker max error ratio must be between 0 and 1"))
+	}
+
+	for tenantID, quota := range c.Worker.TenantQuotas {
+		if quota <= 0 {
+			errs = append(errs, fmt.Errorf("worker tenant quota for %s must be positive", tenantID))
+		}
+	}
+
+	if c.Worker.CustomerDailyCap < 0 {
+		errs = append(errs, errors.New("worker customer daily cap must not be negative"))
+	}
+
+	switch c.Worker.ProcessingOrder {
+	case OrderAlphabetical, OrderLargestFirst, OrderSmallestFirst:
+	case OrderPriority:
+		if len(c.Worker.JourneyPriority) == 0 {
+			errs = append(errs, errors.New("worker journey priority is required for priority processing order"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("worker processing order %q is not supported", c.Worker.ProcessingOrder))
+	}
+
+	for tenantID, key := range c.Signing.Keys {
+		if key == "" {
+			errs = append(errs, fmt.Errorf("message signing key for %s must not be empty", tenantID))
+		}
+	}
+
+	for category, cost := range c.Spend.Costs {
+		if cost < 0 {
+			errs = append(errs, fmt.Errorf("message cost for %s must not be negative", category))
+		}
+	}
+
+	if c.Spend.RunCap < 0 || c.Spend.DailyCap < 0 {
+		errs = append(errs, errors.New("spend caps must not be negative"))
+	}
+
+	if c.Worker.CustomerLock && (c.Worker.CustomerLockTTL < time.Second || c.Worker.CustomerLockWait < 0) {
+		errs = append(errs, errors.New("worker customer lock TTL must be at least 1s and wait must not be negative"))
+	}
+
+	if c.Worker.ScanDropRatio < 0 || c.Worker.ScanDropRatio > 1 {
+		errs = append(errs, errors.New("worker scan drop ratio must be between 0 and 1"))
+	}
+
+	if c.Worker.ScanBaselineRuns <= 0 {
+		errs = append(errs, errors.New("worker scan baseline runs must be positive"))
+	}
+
+	if c.Worker.DeadlineMargin < 0 {
+		errs = append(errs, errors.New("worker deadline margin must not be negative"))
+	}
+
+	if c.Worker.MaxRunJitter < 0 {
+		errs = append(errs, errors.New("worker max run jitter must not be negative"))
+	}
+
+	if c.Worker.CatchUpGap < 0 || c.Worker.CatchUpPace < 0 {
+		errs = append(errs, errors.New("worker catch-up gap and pace must not be negative"))
+	}
+
+	if c.Worker.KillSwitchCacheTTL < 0 {
+		errs = append(errs, errors.New("worker kill-switch cache TTL must not be negative"))
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("config validation failed: %w", errors.Join(errs...))
 	}
@@ -44,6 +153,28 @@ func ValidateJourneyConfig(cfg *JourneyConfig) error {
 		errs = append(errs, errors.New("settings.max_inactive_time.minutes must be positive"))
 	}
 
+	if cfg.Settings.Consent.MaxAge.Minutes < 0 {
+		errs = append(errs, errors.New("settings.consent.max_age.minutes must not be negative"))
+	}
+
+	if cfg.Settings.BurnIn.Minutes < 0 {
+		errs = append(errs, errors.New("settings.burn_in.minutes must not be negative"))
+	}
+
+	if step := cfg.Settings.MinRecoveryStep; step != "" && cfg.FindStep(step) == nil {
+		errs = append(errs, fmt.Errorf("settings.min_recovery_step %q is not a configured step", step))
+	}
+
+	for _, step := range cfg.Settings.TerminalSteps {
+		if cfg.FindStep(step) == nil {
+			errs = append(errs, fmt.Errorf("settings.terminal_steps %q is not a configured step", step))
+		}
+	}
+
+	for i, repique := range cfg.Settings.LifecycleRepiques {
+		errs = append(errs, validateAction(fmt.Sprintf("settings.lifecycle_repiques[%d]", i), repique)...)
+	}
+
 	for i, step := range cfg.Steps {
 		if step.ID == "" {
 			errs = append(errs, fmt.Errorf("steps[%d].id is required", i))
@@ -56,6 +187,14 @@ func ValidateJourneyConfig(cfg *JourneyConfig) error {
 			if repique.MaxAttempts <= 0 {
 				errs = append(errs, fmt.Errorf("steps[%d].repiques[%d].max_attempts must be positive", i, j))
 			}
+			if cond := repique.Condition.TimeInStep; cond != nil {
+				switch cond.Basis {
+				case "", BasisStepEntry, BasisLastInteraction:
+				default:
+					errs = append(errs, fmt.Errorf("steps[%d].repiques[%d].condition.time_in_step.basis %q is not supported", i, j, cond.Basis))
+				}
+			}
+			errs = append(errs, validateAction(fmt.Sprintf("steps[%d].repiques[%d]", i, j), repique)...)
 		}
 	}
 
@@ -65,3 +204,29 @@ func ValidateJourneyConfig(cfg *JourneyConfig) error {
 
 	return nil
 }
+
+// validateAction validates a repique's action. path identifies the repique in error messages.
+// Template names are checked against the template config by the config loader.
+func validateAction(path string, repique Repique) []error {
+	var errs []error
+
+	if j := repique.SendJitter; j != nil {
+		if j.MinSeconds < 0 || j.MaxSeconds < j.MinSeconds {
+			errs = append(errs, fmt.Errorf("%s.send_jitter: need 0 <= min_seconds <= max_seconds", path))
+		}
+		if time.Duration(j.MaxSeconds)*time.Second > MaxSendJitter {
+			errs = append(errs, fmt.Errorf("%s.send_jitter.max_seconds must not exceed %s", path, MaxSendJitter))
+		}
+	}
+
+	for attempt, template := range repique.Action.TemplatesByAttempt {
+		if attempt < 1 || (repique.MaxAttempts > 0 && attempt > repique.MaxAttempts) {
+			errs = append(errs, fmt.Errorf("%s.action.templates_by_attempt: attempt %d is outside 1..max_attempts", path, attempt))
+		}
+		if template == "" {
+			errs = append(errs, fmt.Errorf("%s.action.templates_by_attempt[%d] is empty", path, attempt))
+		}
+	}
+
+	return errs
+}
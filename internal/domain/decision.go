@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Decision records the outcome of evaluating one repique for a customer,
+// whether or not it triggered.
+type Decision struct {
+	JourneyID      string
+	CustomerNumber string
+	Step           string
+	RepiqueID      string
+	Attempt        int // attempt number a send would have been
+	Triggered      bool
+	Reason         string
+	Inactivity     time.Duration // time since the customer's last interaction
+	EvaluatedAt    time.Time
+}
@@ -7,9 +7,16 @@ import (
 
 // Sentinel errors for common conditions.
 var (
-	ErrNotFound       = errors.New("not found")
-	ErrJourneyExpired = errors.New("journey expired")
-	ErrInvalidConfig  = errors.New("invalid configuration")
+	ErrNotFound        = errors.New("not found")
+	ErrJourneyExpired  = errors.New("journey expired")
+	ErrInvalidConfig   = errors.New("invalid configuration")
+	ErrMessageTooLong  = errors.New("message too long")
+	ErrRenderTimeout   = errors.New("template render timed out")
+	ErrRenderTooLarge  = errors.New("template render output too large")
+	ErrRenderFailed    = errors.New("message render failed")
+	ErrRunAborted      = errors.New("run aborted")
+	ErrSpendCapReached = errors.New("spend cap reached")
+	ErrLockHeld        = errors.New("lock held by another worker")
 )
 
 // JourneyError represents an error related to journey processing.
@@ -35,14 +42,22 @@ func (e *JourneyError) Unwrap() error {
 type ConfigError struct {
 	ConfigName string
 	Field      string
+	Line       int    // 1-based line of a parse error, when known
+	Snippet    string // document region around Line
 	Err        error
 }
 
 func (e *ConfigError) Error() string {
+	var msg string
 	if e.Field != "" {
-		return fmt.Sprintf("config %s: field %s: %v", e.ConfigName, e.Field, e.Err)
+		msg = fmt.Sprintf("config %s: field %s: %v", e.ConfigName, e.Field, e.Err)
+	} else {
+		msg = fmt.Sprintf("config %s: %v", e.ConfigName, e.Err)
 	}
-	return fmt.Sprintf("config %s: %v", e.ConfigName, e.Err)
+	if e.Snippet != "" {
+		msg += "\n" + e.Snippet
+	}
+	return msg
 }
 
 func (e *ConfigError) Unwrap() error {
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Lifecycle event types.
+const (
+	EventRecoverySent    = "recovery_sent"
+	EventJourneyExpired  = "journey_expired"
+	EventJourneyFinished = "journey_finished"
+)
+
+// LifecycleEvent describes a journey lifecycle transition for other systems.
+type LifecycleEvent struct {
+	Type           string    `json:"type"`
+	JourneyID      string    `json:"journey_id"`
+	CustomerNumber string    `json:"customer_number"`
+	TenantID       string    `json:"tenant_id"`
+	Step           string    `json:"step,omitempty"`
+	RepiqueID      string    `json:"repique_id,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// NewLifecycleEvent creates an event of the given type for a journey state.
+func NewLifecycleEvent(eventType string, state *JourneyState, repiqueID string) LifecycleEvent {
+	return LifecycleEvent{
+		Type:           eventType,
+		JourneyID:      state.JourneyID,
+		CustomerNumber: state.CustomerNumber,
+		TenantID:       state.TenantID,
+		Step:           state.Step,
+		RepiqueID:      repiqueID,
+		OccurredAt:     time.Now().UTC(),
+	}
+}
@@ -2,8 +2,12 @@ package domain
 
 import "time"
 
+// CurrentStateSchemaVersion is the schema version written for journey states.
+const CurrentStateSchemaVersion = 1
+
 // JourneyState represents the current state of a customer's journey.
 type JourneyState struct {
+	SchemaVersion     int            `json:"schema_version"`
 	JourneyID         string         `json:"journey_id"`
 	Step              string         `json:"step"`
 	CustomerNumber    string         `json:"customer_number"`
@@ -12,7 +16,11 @@ type JourneyState struct {
 	LastInteractionAt time.Time      `json:"last_interaction_at"`
 	StepStartedAt     time.Time      `json:"step_started_at"`
 	JourneyStartedAt  time.Time      `json:"journey_started_at"`
+	ConsentAt         time.Time      `json:"consent_at,omitempty"`
 	Metadata          map[string]any `json:"metadata"`
+
+	// Partial is set when the state was loaded without metadata by a light scan.
+	Partial bool `json:"-"`
 }
 
 // RepiqueAttempts tracks how many times each repique has been sent.
@@ -27,11 +35,50 @@ func NewRepiqueAttempts() *RepiqueAttempts {
 	}
 }
 
+// Upgrade fills defaults for fields missing from states written by older
+// schema versions. It reports whether the state was changed.
+func (s *JourneyState) Upgrade() bool {
+	if s.SchemaVersion >= CurrentStateSchemaVersion {
+		return false
+	}
+
+	// v0 -> v1: timestamps and metadata may be absent.
+	if s.StepStartedAt.IsZero() {
+		s.StepStartedAt = s.LastInteractionAt
+	}
+	if s.JourneyStartedAt.IsZero() {
+		s.JourneyStartedAt = s.StepStartedAt
+	}
+	if s.ConsentAt.IsZero() {
+		s.ConsentAt = s.LastInteractionAt
+	}
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]any)
+	}
+
+	s.SchemaVersion = CurrentStateSchemaVersion
+	return true
+}
+
 // IsExpired checks if the journey has expired based on max inactive time.
 func (s *JourneyState) IsExpired(maxInactiveTime time.Duration) bool {
 	return time.Since(s.LastInteractionAt) >= maxInactiveTime
 }
 
+// IsConsentExpired checks if the customer's consent is older than maxAge.
+// A state with no recorded consent is treated as expired.
+func (s *JourneyState) IsConsentExpired(maxAge time.Duration) bool {
+	return s.ConsentAt.IsZero() || time.Since(s.ConsentAt) >= maxAge
+}
+
+// ForceAge moves the last interaction and step start back so the journey
+// appears to have been inactive for age. It is meant for tests and QA.
+func (s *JourneyState) ForceAge(age time.Duration) {
+	agedAt := time.Now().Add(-age)
+	s.LastInteractionAt = agedAt
+	s.StepStartedAt = agedAt
+}
+
 // TimeInStep returns how long the customer has been in the current step.
 func (s *JourneyState) TimeInStep() time.Duration {
 	return time.Since(s.StepStartedAt)
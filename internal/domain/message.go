@@ -2,13 +2,15 @@ package domain
 
 // Message represents a message to be sent to a customer.
 type Message struct {
-	CustomerNumber string         `json:"customer_number"`
-	TenantID       string         `json:"tenant_id"`
-	ContactID      string         `json:"contact_id"`
-	Template       string         `json:"template"`
-	RepiqueID      string         `json:"repique_id"`
-	Step           string         `json:"step,omitempty"`
-	Metadata       map[string]any `json:"metadata"`
+	CustomerNumber   string         `json:"customer_number"`
+	TenantID         string         `json:"tenant_id"`
+	JourneyID        string         `json:"journey_id"`
+	ContactID        string         `json:"contact_id"`
+	Template         string         `json:"template"`
+	FallbackTemplate string         `json:"fallback_template,omitempty"` // used when Template cannot be loaded
+	RepiqueID        string         `json:"repique_id"`
+	Step             string         `json:"step,omitempty"`
+	Metadata         map[string]any `json:"metadata"`
 }
 
 // NewMessage creates a new Message from journey state and repique info.
@@ -16,6 +18,7 @@ func NewMessage(state *JourneyState, repiqueID, template, step string) Message {
 	return Message{
 		CustomerNumber: state.CustomerNumber,
 		TenantID:       state.TenantID,
+		JourneyID:      state.JourneyID,
 		ContactID:      state.ContactID,
 		Template:       template,
 		RepiqueID:      repiqueID,
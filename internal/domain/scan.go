@@ -0,0 +1,24 @@
+package domain
+
+// ScanStats describes how much of the keyspace a scan covered.
+type ScanStats struct {
+	Iterations   int // SCAN calls made
+	KeysMatched  int // keys returned by SCAN
+	KeysFetched  int // keys read and decoded
+	KeysFailed   int // keys that could not be read or decoded
+	NodesVisited int // nodes scanned; 1 outside cluster mode
+
+	// Truncated is set when the scan stopped at its duration budget, so the
+	// journeys returned are only part of the keyspace.
+	Truncated bool
+}
+
+// Add accumulates the stats of another scan.
+func (s *ScanStats) Add(other ScanStats) {
+	s.Iterations += other.Iterations
+	s.KeysMatched += other.KeysMatched
+	s.KeysFetched += other.KeysFetched
+	s.KeysFailed += other.KeysFailed
+	s.NodesVisited += other.NodesVisited
+	s.Truncated = s.Truncated || other.Truncated
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Units supported by CloudWatch metrics.
+const (
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+)
+
+// Metric is a single metric value.
+type Metric struct {
+	Name  string
+	Unit  string
+	Value float64
+}
+
+// EMFEmitter writes metrics as CloudWatch Embedded Metric Format log lines.
+// In Lambda, CloudWatch extracts them from stdout without an agent.
+type EMFEmitter struct {
+	output    io.Writer
+	namespace string
+	now       func() time.Time
+}
+
+// NewEMFEmitter creates an emitter that writes to output under the given namespace.
+func NewEMFEmitter(output io.Writer, namespace string) *EMFEmitter {
+	return &EMFEmitter{
+		output:    output,
+		namespace: namespace,
+		now:       time.Now,
+	}
+}
+
+type emfMetadata struct {
+	Timestamp         int64                `json:"Timestamp"`
+	CloudWatchMetrics []emfMetricDirective `json:"CloudWatchMetrics"`
+}
+
+type emfMetricDirective struct {
+	Namespace  string         `json:"Namespace"`
+	Dimensions [][]string     `json:"Dimensions"`
+	Metrics    []emfMetricDef `json:"Metrics"`
+}
+
+type emfMetricDef struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit,omitempty"`
+}
+
+// Emit writes one EMF log line with the given dimensions and metrics.
+func (e *EMFEmitter) Emit(dimensions map[string]string, metrics []Metric) error {
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	defs := make([]emfMetricDef, 0, len(metrics))
+	doc := make(map[string]any, len(dimensions)+len(metrics)+1)
+	for _, k := range keys {
+		doc[k] = dimensions[k]
+	}
+	for _, m := range metrics {
+		defs = append(defs, emfMetricDef{Name: m.Name, Unit: m.Unit})
+		doc[m.Name] = m.Value
+	}
+
+	doc["_aws"] = emfMetadata{
+		Timestamp: e.now().UnixMilli(),
+		CloudWatchMetrics: []emfMetricDirective{{
+			Namespace:  e.namespace,
+			Dimensions: [][]string{keys},
+			Metrics:    defs,
+		}},
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal emf: %w", err)
+	}
+
+	if _, err := fmt.Fprintln(e.output, string(data)); err != nil {
+		return fmt.Errorf("write emf: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// ScanBaseline keeps the journey counts of recent runs.
+type ScanBaseline interface {
+	// RecentCounts returns the scanned journey counts of recent runs, newest first.
+	RecentCounts(ctx context.Context) ([]int, error)
+
+	// RecordCount records the scanned journey count of the current run.
+	RecordCount(ctx context.Context, count int) error
+}
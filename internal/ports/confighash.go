@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// ConfigHashStore remembers the hash of the last loaded configuration of each
+// journey, so config changes can be detected across runs.
+type ConfigHashStore interface {
+	// SwapConfigHash stores hash as the journey's current config hash and
+	// returns the previous one. The boolean is false when none was stored.
+	SwapConfigHash(ctx context.Context, journeyID, hash string) (string, bool, error)
+}
@@ -1,11 +1,13 @@
 package ports
 
 import (
+	"context"
+
 	"worker-project/internal/config"
 )
 
 // JourneyConfigLoader loads journey configurations.
 type JourneyConfigLoader interface {
 	// LoadJourneyConfig loads configuration for a specific journey.
-	LoadJourneyConfig(journeyID string) (*config.JourneyConfig, error)
+	LoadJourneyConfig(ctx context.Context, journeyID string) (*config.JourneyConfig, error)
 }
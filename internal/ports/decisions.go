@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"worker-project/internal/domain"
+)
+
+// DecisionLog records repique evaluation decisions for offline analysis.
+type DecisionLog interface {
+	// Record records a single evaluation decision.
+	Record(ctx context.Context, decision domain.Decision) error
+}
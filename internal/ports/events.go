@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"worker-project/internal/domain"
+)
+
+// EventPublisher publishes journey lifecycle events to other systems.
+type EventPublisher interface {
+	// Publish publishes a single lifecycle event.
+	Publish(ctx context.Context, event domain.LifecycleEvent) error
+}
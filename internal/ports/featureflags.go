@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"worker-project/internal/domain"
+)
+
+// FeatureFlagEvaluator evaluates feature flags for a customer.
+type FeatureFlagEvaluator interface {
+	// IsEnabled reports whether a flag is enabled for the customer of a journey state.
+	IsEnabled(ctx context.Context, flag string, state *domain.JourneyState) (bool, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// JourneyTracker records when the worker first saw each journey.
+type JourneyTracker interface {
+	// FirstSeen records now as the journey's first-seen time unless one is
+	// already recorded, and returns the recorded time.
+	FirstSeen(ctx context.Context, journeyID string, now time.Time) (time.Time, error)
+}
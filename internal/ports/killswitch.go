@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// KillSwitch reports whether a repique has been disabled at runtime.
+type KillSwitch interface {
+	// IsActive reports whether the kill-switch for a repique is set.
+	IsActive(ctx context.Context, journeyID, repiqueID string) (bool, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// CustomerLocker serializes processing of a customer across workers.
+type CustomerLocker interface {
+	// Acquire takes the customer's processing lock. It returns
+	// domain.ErrLockHeld when another worker holds the lock.
+	Acquire(ctx context.Context, customerNumber string) (release func(context.Context) error, err error)
+}
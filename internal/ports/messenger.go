@@ -14,8 +14,11 @@ type Messenger interface {
 
 // Template represents a message template.
 type Template struct {
-	Channel string
-	Content TemplateContent
+	Ref      string // reference the template was loaded from
+	Channel  string
+	Category string // provider pricing category, e.g. "marketing" or "utility"
+	Content  TemplateContent
+	Split    SplitOptions
 }
 
 // TemplateContent holds the template content details.
@@ -24,10 +27,18 @@ type TemplateContent struct {
 	Body string
 }
 
+// SplitOptions controls how an over-length body is split into several messages.
+// When Enabled is false, an over-length body is rejected.
+type SplitOptions struct {
+	Enabled  bool
+	MaxParts int
+	Numbered bool
+}
+
 // TemplateRenderer loads and renders message templates.
 type TemplateRenderer interface {
 	// LoadTemplate loads a template by reference.
-	LoadTemplate(templateRef string) (*Template, error)
+	LoadTemplate(ctx context.Context, templateRef string) (*Template, error)
 
 	// Render applies metadata to a template and returns the rendered content.
 	Render(template *Template, metadata map[string]any) (string, error)
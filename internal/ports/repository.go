@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"worker-project/internal/domain"
 )
@@ -19,4 +20,33 @@ type StateRepository interface {
 
 	// DeleteJourneyState removes a journey state.
 	DeleteJourneyState(ctx context.Context, journeyID, customerNumber string) error
+
+	// IncrementFailureCount increments and returns the consecutive processing failure count.
+	IncrementFailureCount(ctx context.Context, journeyID, customerNumber string) (int, error)
+
+	// ResetFailureCount clears the consecutive processing failure count, if any.
+	ResetFailureCount(ctx context.Context, journeyID, customerNumber string) error
+
+	// IncrementTenantSendCount increments and returns today's send count of a tenant.
+	IncrementTenantSendCount(ctx context.Context, tenantID string) (int, error)
+
+	// GetTenantSendCount returns today's send count of a tenant.
+	GetTenantSendCount(ctx context.Context, tenantID string) (int, error)
+
+	// IncrementCustomerSendCount increments and returns today's send count of a customer across journeys.
+	IncrementCustomerSendCount(ctx context.Context, customerNumber string) (int, error)
+
+	// GetCustomerSendCount returns today's send count of a customer across journeys.
+	GetCustomerSendCount(ctx context.Context, customerNumber string) (int, error)
+
+	// DeadLetterJourney moves a journey state out of processing.
+	DeadLetterJourney(ctx context.Context, journeyID, customerNumber string) error
+}
+
+// JourneyAger rewinds journey states to simulate inactivity, so expiry and
+// repique eligibility can be triggered without waiting. It must only be used
+// outside production.
+type JourneyAger interface {
+	// ForceAge makes a journey appear to have been inactive for age.
+	ForceAge(ctx context.Context, journeyID, customerNumber string, age time.Duration) error
 }
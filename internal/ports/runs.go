@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RunTracker records when the worker last completed a run.
+type RunTracker interface {
+	// LastRunAt returns the completion time of the last successful run.
+	// The boolean is false when no run has been recorded.
+	LastRunAt(ctx context.Context) (time.Time, bool, error)
+
+	// RecordRun records the completion time of a successful run.
+	RecordRun(ctx context.Context, at time.Time) error
+}
@@ -8,9 +8,13 @@ import (
 
 // JourneyScanner scans for active journeys in the data store.
 type JourneyScanner interface {
-	// ScanAllJourneys returns all active journey states.
-	ScanAllJourneys(ctx context.Context) ([]*domain.JourneyState, error)
+	// ScanAllJourneys returns all active journey states and the scan's coverage.
+	ScanAllJourneys(ctx context.Context) ([]*domain.JourneyState, domain.ScanStats, error)
+
+	// ScanAllJourneysLight returns all active journey states without metadata.
+	// Callers must load the full state before using metadata.
+	ScanAllJourneysLight(ctx context.Context) ([]*domain.JourneyState, domain.ScanStats, error)
 
 	// ScanJourneys returns active journey states for a specific journey ID.
-	ScanJourneys(ctx context.Context, journeyID string) ([]*domain.JourneyState, error)
+	ScanJourneys(ctx context.Context, journeyID string) ([]*domain.JourneyState, domain.ScanStats, error)
 }
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// URLShortener shortens links in outgoing messages.
+type URLShortener interface {
+	// Shorten returns a short URL redirecting to the given URL.
+	Shorten(ctx context.Context, url string) (string, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// SpendLedger persists the daily message spend so caps hold across runs.
+type SpendLedger interface {
+	// DailySpend returns today's accumulated spend.
+	DailySpend(ctx context.Context) (float64, error)
+
+	// AddSpend adds to today's spend and returns the new total.
+	AddSpend(ctx context.Context, amount float64) (float64, error)
+}
@@ -12,6 +12,15 @@ type EvaluationResult struct {
 	ShouldTrigger bool
 	Repique       *config.Repique
 	Reason        string
+
+	// NearCap is set when the triggered send leaves exactly one attempt
+	// before the repique's max attempts.
+	NearCap bool
+}
+
+// isNearCap reports whether the next send brings the repique to one attempt below its cap.
+func isNearCap(repique *config.Repique, attempts *domain.RepiqueAttempts) bool {
+	return repique.MaxAttempts > 1 && attempts.Attempts[repique.ID]+1 == repique.MaxAttempts-1
 }
 
 // EvaluateLifecycleRepique checks if a lifecycle repique should trigger.
@@ -36,6 +45,7 @@ func EvaluateLifecycleRepique(
 			ShouldTrigger: true,
 			Repique:       repique,
 			Reason:        "journey expired",
+			NearCap:       isNearCap(repique, attempts),
 		}
 	}
 
@@ -49,6 +59,7 @@ func EvaluateLifecycleRepique(
 				ShouldTrigger: true,
 				Repique:       repique,
 				Reason:        "before expiry window reached",
+				NearCap:       isNearCap(repique, attempts),
 			}
 		}
 	}
@@ -76,15 +87,20 @@ func EvaluateStepRepique(
 	}
 
 	// Check time_in_step condition
-	if repique.Condition.TimeInStep != nil {
-		requiredTime := time.Duration(repique.Condition.TimeInStep.GteMinutes) * time.Minute
-		timeInStep := state.TimeInStep()
+	if cond := repique.Condition.TimeInStep; cond != nil {
+		requiredTime := time.Duration(cond.GteMinutes) * time.Minute
+
+		elapsed, reason := state.TimeInStep(), "time in step threshold reached"
+		if cond.Basis == config.BasisLastInteraction {
+			elapsed, reason = state.TimeSinceLastInteraction(), "inactivity threshold reached"
+		}
 
-		if timeInStep >= requiredTime {
+		if elapsed >= requiredTime {
 			return EvaluationResult{
 				ShouldTrigger: true,
 				Repique:       repique,
-				Reason:        "time in step threshold reached",
+				Reason:        reason,
+				NearCap:       isNearCap(repique, attempts),
 			}
 		}
 	}
@@ -96,35 +112,58 @@ func EvaluateStepRepique(
 	}
 }
 
-// FindTriggeredLifecycleRepiques returns all lifecycle repiques that should trigger.
-func FindTriggeredLifecycleRepiques(
+// EvaluateLifecycleRepiques evaluates every lifecycle repique, triggered or not.
+func EvaluateLifecycleRepiques(
 	repiques []config.Repique,
 	attempts *domain.RepiqueAttempts,
 	state *domain.JourneyState,
 	maxInactiveTime time.Duration,
 ) []EvaluationResult {
-	var results []EvaluationResult
+	results := make([]EvaluationResult, 0, len(repiques))
 	for i := range repiques {
-		result := EvaluateLifecycleRepique(&repiques[i], attempts, state, maxInactiveTime)
-		if result.ShouldTrigger {
-			results = append(results, result)
-		}
+		results = append(results, EvaluateLifecycleRepique(&repiques[i], attempts, state, maxInactiveTime))
 	}
 	return results
 }
 
+// EvaluateStepRepiques evaluates every step repique, triggered or not.
+func EvaluateStepRepiques(
+	repiques []config.Repique,
+	attempts *domain.RepiqueAttempts,
+	state *domain.JourneyState,
+) []EvaluationResult {
+	results := make([]EvaluationResult, 0, len(repiques))
+	for i := range repiques {
+		results = append(results, EvaluateStepRepique(&repiques[i], attempts, state))
+	}
+	return results
+}
+
+// FindTriggeredLifecycleRepiques returns all lifecycle repiques that should trigger.
+func FindTriggeredLifecycleRepiques(
+	repiques []config.Repique,
+	attempts *domain.RepiqueAttempts,
+	state *domain.JourneyState,
+	maxInactiveTime time.Duration,
+) []EvaluationResult {
+	return triggeredOnly(EvaluateLifecycleRepiques(repiques, attempts, state, maxInactiveTime))
+}
+
 // FindTriggeredStepRepiques returns all step repiques that should trigger.
 func FindTriggeredStepRepiques(
 	repiques []config.Repique,
 	attempts *domain.RepiqueAttempts,
 	state *domain.JourneyState,
 ) []EvaluationResult {
-	var results []EvaluationResult
-	for i := range repiques {
-		result := EvaluateStepRepique(&repiques[i], attempts, state)
+	return triggeredOnly(EvaluateStepRepiques(repiques, attempts, state))
+}
+
+func triggeredOnly(results []EvaluationResult) []EvaluationResult {
+	var triggered []EvaluationResult
+	for _, result := range results {
 		if result.ShouldTrigger {
-			results = append(results, result)
+			triggered = append(triggered, result)
 		}
 	}
-	return results
+	return triggered
 }
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+)
+
+func TestForceAgedStateTriggersRepiques(t *testing.T) {
+	maxInactiveTime := 2 * time.Hour
+	lifecycle := []config.Repique{
+		{ID: "last_call", MaxAttempts: 1, Trigger: config.Trigger{BeforeExpire: &config.Duration{Minutes: 30}}},
+		{ID: "expired", MaxAttempts: 1, Trigger: config.Trigger{OnExpire: true}},
+	}
+	step := []config.Repique{
+		{ID: "nudge", MaxAttempts: 1, Condition: config.Condition{TimeInStep: &config.TimeCondition{GteMinutes: 60}}},
+	}
+
+	tests := []struct {
+		name          string
+		age           time.Duration
+		wantLifecycle []string
+		wantStep      []string
+	}{
+		{name: "fresh", age: 0},
+		{name: "past the step threshold", age: 61 * time.Minute, wantStep: []string{"nudge"}},
+		{name: "in the before expiry window", age: 100 * time.Minute, wantLifecycle: []string{"last_call"}, wantStep: []string{"nudge"}},
+		{name: "expired", age: 3 * time.Hour, wantLifecycle: []string{"expired"}, wantStep: []string{"nudge"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &domain.JourneyState{JourneyID: "checkout", CustomerNumber: "5511999990000", Step: "cart"}
+			state.ForceAge(tt.age)
+			attempts := domain.NewRepiqueAttempts()
+
+			gotLifecycle := triggeredIDs(FindTriggeredLifecycleRepiques(lifecycle, attempts, state, maxInactiveTime))
+			if !slices.Equal(gotLifecycle, tt.wantLifecycle) {
+				t.Errorf("lifecycle repiques triggered = %v, want %v", gotLifecycle, tt.wantLifecycle)
+			}
+			gotStep := triggeredIDs(FindTriggeredStepRepiques(step, attempts, state))
+			if !slices.Equal(gotStep, tt.wantStep) {
+				t.Errorf("step repiques triggered = %v, want %v", gotStep, tt.wantStep)
+			}
+		})
+	}
+}
+
+func triggeredIDs(results []EvaluationResult) []string {
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.Repique.ID)
+	}
+	return ids
+}
+
+func TestEvaluateStepRepiqueBasis(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		basis           string
+		stepEntered     time.Duration
+		lastInteraction time.Duration
+		want            bool
+		wantReason      string
+	}{
+		{name: "default measures from step entry", stepEntered: 2 * time.Hour, lastInteraction: 10 * time.Minute, want: true, wantReason: "time in step threshold reached"},
+		{name: "step_entry with a recent interaction", basis: config.BasisStepEntry, stepEntered: 2 * time.Hour, lastInteraction: 10 * time.Minute, want: true, wantReason: "time in step threshold reached"},
+		{name: "last_interaction with a recent interaction", basis: config.BasisLastInteraction, stepEntered: 2 * time.Hour, lastInteraction: 10 * time.Minute, wantReason: "conditions not met"},
+		{name: "step_entry in a freshly entered step", basis: config.BasisStepEntry, stepEntered: 10 * time.Minute, lastInteraction: 2 * time.Hour, wantReason: "conditions not met"},
+		{name: "last_interaction in a freshly entered step", basis: config.BasisLastInteraction, stepEntered: 10 * time.Minute, lastInteraction: 2 * time.Hour, want: true, wantReason: "inactivity threshold reached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repique := &config.Repique{
+				ID:          "nudge",
+				MaxAttempts: 1,
+				Condition:   config.Condition{TimeInStep: &config.TimeCondition{GteMinutes: 60, Basis: tt.basis}},
+			}
+			state := &domain.JourneyState{
+				StepStartedAt:     now.Add(-tt.stepEntered),
+				LastInteractionAt: now.Add(-tt.lastInteraction),
+			}
+
+			result := EvaluateStepRepique(repique, domain.NewRepiqueAttempts(), state)
+			if result.ShouldTrigger != tt.want || result.Reason != tt.wantReason {
+				t.Errorf("EvaluateStepRepique = (%v, %q), want (%v, %q)", result.ShouldTrigger, result.Reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
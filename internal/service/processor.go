@@ -2,34 +2,115 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math/rand"
+	"time"
 
 	"worker-project/internal/config"
 	"worker-project/internal/domain"
 	"worker-project/internal/ports"
 )
 
+// ReoptinRepiqueID is the attempt key used for re-opt-in messages sent when consent expires.
+const ReoptinRepiqueID = "consent_reoptin"
+
+// errJourneyEnded signals that a repique ended the journey and no further
+// repiques should be processed for it.
+var errJourneyEnded = errors.New("journey ended")
+
+// lockPollInterval is how often a held customer lock is retried.
+const lockPollInterval = 100 * time.Millisecond
+
+// errSendSkipped signals that a send was skipped and already logged.
+var errSendSkipped = errors.New("send skipped")
+
+// ProcessorConfig holds processor behavior settings.
+type ProcessorConfig struct {
+	// TestCustomers are customer numbers whose attempt caps are bypassed.
+	// It must only be populated outside production.
+	TestCustomers []string
+
+	// TenantQuotas caps daily sends per tenant ID. Tenants without an entry
+	// are unlimited.
+	TenantQuotas map[string]int
+
+	// CustomerDailyCap caps a customer's daily sends across journeys.
+	// Zero disables the cap; test customers are exempt.
+	CustomerDailyCap int
+
+	// CustomerLockWait is how long to wait for a customer's processing lock
+	// before skipping the customer until the next run. The wait blocks the
+	// caller; zero tries the lock once.
+	CustomerLockWait time.Duration
+
+	// MaxRunJitter bounds the total send_jitter delay of the processor's run.
+	// Delays past it, or running into DeadlineMargin of the context deadline,
+	// are skipped. Zero disables send jitter.
+	MaxRunJitter   time.Duration
+	DeadlineMargin time.Duration
+}
+
 // Processor handles journey processing and message sending.
 type Processor struct {
-	repository ports.StateRepository
-	messenger  ports.Messenger
-	logger     *slog.Logger
+	repository     ports.StateRepository
+	messenger      ports.Messenger
+	killSwitch     ports.KillSwitch
+	flags          ports.FeatureFlagEvaluator
+	events         ports.EventPublisher
+	decisions      ports.DecisionLog
+	locker         ports.CustomerLocker
+	lockWait       time.Duration
+	jitterBudget   time.Duration
+	jitterSpent    time.Duration
+	deadlineMargin time.Duration
+	testCustomers  map[string]bool
+	tenantQuotas   map[string]int
+	customerCap    int
+	quotaSkipped   int
+	capSkipped     int
+	logger         *slog.Logger
 }
 
 // NewProcessor creates a new processor with injected dependencies.
 func NewProcessor(
 	repository ports.StateRepository,
 	messenger ports.Messenger,
+	killSwitch ports.KillSwitch,
+	flags ports.FeatureFlagEvaluator,
+	events ports.EventPublisher,
+	decisions ports.DecisionLog,
+	locker ports.CustomerLocker,
+	cfg ProcessorConfig,
 	logger *slog.Logger,
 ) *Processor {
+	testCustomers := make(map[string]bool, len(cfg.TestCustomers))
+	for _, number := range cfg.TestCustomers {
+		testCustomers[number] = true
+	}
+
 	return &Processor{
-		repository: repository,
-		messenger:  messenger,
-		logger:     logger,
+		repository:     repository,
+		messenger:      messenger,
+		killSwitch:     killSwitch,
+		flags:          flags,
+		events:         events,
+		decisions:      decisions,
+		locker:         locker,
+		lockWait:       cfg.CustomerLockWait,
+		jitterBudget:   cfg.MaxRunJitter,
+		deadlineMargin: cfg.DeadlineMargin,
+		testCustomers:  testCustomers,
+		tenantQuotas:   cfg.TenantQuotas,
+		customerCap:    cfg.CustomerDailyCap,
+		logger:         logger,
 	}
 }
 
 // ProcessJourney checks a single customer journey and sends messages if needed.
+// Messages that fail to build or send are returned as one error once the
+// customer's remaining repiques have been processed.
 func (p *Processor) ProcessJourney(ctx context.Context, cfg *config.JourneyConfig, state *domain.JourneyState) error {
 	logger := p.logger.With(
 		"journey_id", state.JourneyID,
@@ -37,8 +118,44 @@ func (p *Processor) ProcessJourney(ctx context.Context, cfg *config.JourneyConfi
 		"step", state.Step,
 	)
 
+	if !cfg.Settings.IsEnabled() {
+		logger.Debug("journey disabled, skipping")
+		return nil
+	}
+
+	if !cfg.IsRecoveryEligible(state.Step) {
+		logger.Info("customer skipped", "reason", "step before min recovery step", "min_recovery_step", cfg.Settings.MinRecoveryStep)
+		return nil
+	}
+
+	if p.locker != nil {
+		release, err := p.lockCustomer(ctx, state.CustomerNumber)
+		if errors.Is(err, domain.ErrLockHeld) {
+			logger.Info("customer skipped", "reason", "customer locked by another worker")
+			return nil
+		}
+		if err != nil {
+			return &domain.JourneyError{
+				JourneyID:      state.JourneyID,
+				CustomerNumber: state.CustomerNumber,
+				Op:             "LockCustomer",
+				Err:            err,
+			}
+		}
+		defer func() {
+			if err := release(context.WithoutCancel(ctx)); err != nil {
+				logger.Warn("failed to release customer lock", "error", err)
+			}
+		}()
+	}
+
 	logger.Debug("processing journey")
 
+	if cfg.IsTerminalStep(state.Step) {
+		logger.Info("customer reached terminal step, finishing journey")
+		return p.endJourney(ctx, state, "", logger)
+	}
+
 	attempts, err := p.repository.GetRepiqueAttempts(ctx, state.JourneyID, state.CustomerNumber)
 	if err != nil {
 		return &domain.JourneyError{
@@ -49,6 +166,17 @@ func (p *Processor) ProcessJourney(ctx context.Context, cfg *config.JourneyConfi
 		}
 	}
 
+	// Test customers are evaluated as if no attempts had been made
+	if p.testCustomers[state.CustomerNumber] {
+		logger.Warn("attempt caps bypassed for test customer", "recorded_attempts", attempts.Attempts)
+		attempts = domain.NewRepiqueAttempts()
+	}
+
+	// Check if consent has expired
+	if maxAge := cfg.Settings.Consent.MaxAge.ToDuration(); maxAge > 0 && state.IsConsentExpired(maxAge) {
+		return p.handleExpiredConsent(ctx, cfg, state, attempts, logger)
+	}
+
 	maxInactiveTime := cfg.Settings.MaxInactiveTime.ToDuration()
 
 	// Check if journey has expired
@@ -57,15 +185,109 @@ func (p *Processor) ProcessJourney(ctx context.Context, cfg *config.JourneyConfi
 	}
 
 	// Process lifecycle repiques
-	if err := p.processLifecycleRepiques(ctx, cfg, state, attempts, logger); err != nil {
-		logger.Error("error processing lifecycle repiques", "error", err)
+	lifecycleErr := p.processLifecycleRepiques(ctx, cfg, state, attempts, logger)
+	if errors.Is(lifecycleErr, errJourneyEnded) {
+		return nil
 	}
 
 	// Process step repiques
-	if err := p.processStepRepiques(ctx, cfg, state, attempts, logger); err != nil {
-		logger.Error("error processing step repiques", "error", err)
+	stepErr := p.processStepRepiques(ctx, cfg, state, attempts, logger)
+	if errors.Is(stepErr, errJourneyEnded) {
+		stepErr = nil
+	}
+
+	// Failed messages are reported after every repique had its chance, so one
+	// failure does not hold back the customer's other sends.
+	if err := errors.Join(lifecycleErr, stepErr); err != nil {
+		return &domain.JourneyError{
+			JourneyID:      state.JourneyID,
+			CustomerNumber: state.CustomerNumber,
+			Op:             "ProcessRepiques",
+			Err:            err,
+		}
+	}
+
+	return nil
+}
+
+// ObserveJourney evaluates a customer journey and records the decisions
+// without sending messages or changing any state. It is used while a journey
+// is in its observe-only burn-in.
+func (p *Processor) ObserveJourney(ctx context.Context, cfg *config.JourneyConfig, state *domain.JourneyState) error {
+	logger := p.logger.With(
+		"journey_id", state.JourneyID,
+		"customer_number", state.CustomerNumber,
+		"step", state.Step,
+	)
+
+	if !cfg.Settings.IsEnabled() || !cfg.IsRecoveryEligible(state.Step) {
+		return nil
+	}
+
+	if cfg.IsTerminalStep(state.Step) {
+		logger.Info("customer reached terminal step, not finishing while observing")
+		return nil
+	}
+
+	attempts, err := p.repository.GetRepiqueAttempts(ctx, state.JourneyID, state.CustomerNumber)
+	if err != nil {
+		return &domain.JourneyError{
+			JourneyID:      state.JourneyID,
+			CustomerNumber: state.CustomerNumber,
+			Op:             "GetRepiqueAttempts",
+			Err:            err,
+		}
+	}
+
+	results := EvaluateLifecycleRepiques(cfg.Settings.LifecycleRepiques, attempts, state, cfg.Settings.MaxInactiveTime.ToDuration())
+	if step := cfg.FindStep(state.Step); step != nil {
+		results = append(results, EvaluateStepRepiques(step.Repiques, attempts, state)...)
 	}
+	p.recordDecisions(ctx, state, attempts, results, logger)
 
+	for _, result := range triggeredOnly(results) {
+		logger.Info("repique observed, not sent", "repique_id", result.Repique.ID, "reason", result.Reason)
+	}
+	return nil
+}
+
+func (p *Processor) handleExpiredConsent(
+	ctx context.Context,
+	cfg *config.JourneyConfig,
+	state *domain.JourneyState,
+	attempts *domain.RepiqueAttempts,
+	logger *slog.Logger,
+) error {
+	template := cfg.Settings.Consent.ReoptinTemplate
+	if template == "" || attempts.Attempts[ReoptinRepiqueID] > 0 {
+		logger.Info("sends blocked", "reason", "consent expired")
+		return nil
+	}
+
+	if p.isQuotaReached(ctx, state, ReoptinRepiqueID, logger) ||
+		p.isCustomerCapReached(ctx, state, ReoptinRepiqueID, logger) {
+		return nil
+	}
+
+	msg, err := p.newMessage(ctx, state, ReoptinRepiqueID, template, "")
+	if err != nil {
+		logger.Error("failed to build re-opt-in message", "error", err)
+		return err
+	}
+
+	if err := p.send(ctx, state, msg, logger); err != nil {
+		if errors.Is(err, errSendSkipped) {
+			return nil
+		}
+		logger.Error("failed to send re-opt-in message", "error", err)
+		return err
+	}
+
+	if err := p.repository.IncrementRepiqueAttempt(ctx, state.JourneyID, state.CustomerNumber, ReoptinRepiqueID); err != nil {
+		logger.Error("failed to increment repique attempt", "repique_id", ReoptinRepiqueID, "error", err)
+	}
+
+	logger.Info("sent re-opt-in message", "reason", "consent expired")
 	return nil
 }
 
@@ -77,22 +299,47 @@ func (p *Processor) handleExpiredJourney(
 	logger *slog.Logger,
 ) error {
 	logger.Info("journey expired")
+	p.publish(ctx, domain.NewLifecycleEvent(domain.EventJourneyExpired, state, ""), logger)
 
 	maxInactiveTime := cfg.Settings.MaxInactiveTime.ToDuration()
 
-	for i := range cfg.Settings.LifecycleRepiques {
-		repique := &cfg.Settings.LifecycleRepiques[i]
+	results := EvaluateLifecycleRepiques(cfg.Settings.LifecycleRepiques, attempts, state, maxInactiveTime)
+	p.recordDecisions(ctx, state, attempts, results, logger)
 
-		result := EvaluateLifecycleRepique(repique, attempts, state, maxInactiveTime)
+	var failures []error
+	for _, result := range results {
+		repique := result.Repique
 		if !result.ShouldTrigger {
 			continue
 		}
 
-		if repique.Action.Template != "" {
-			msg := domain.NewMessage(state, repique.ID, repique.Action.Template, "")
+		if p.isKilled(ctx, state.JourneyID, repique.ID, logger) ||
+			!p.isFlagEnabled(ctx, state, repique.ID, logger) ||
+			p.isQuotaReached(ctx, state, repique.ID, logger) ||
+			p.isCustomerCapReached(ctx, state, repique.ID, logger) {
+			continue
+		}
+
+		warnNearCap(result, attempts, logger)
 
-			if err := p.messenger.Send(ctx, msg); err != nil {
-				logger.Error("failed to send on_expire message", "repique_id", repique.ID, "error", err)
+		if template := repique.Action.TemplateForAttempt(attempts.Attempts[repique.ID] + 1); template != "" {
+			msg, err := p.newMessage(ctx, state, repique.ID, template, "")
+			if err != nil {
+				logger.Error("failed to build on_expire message", "repique_id", repique.ID, "error", err)
+				failures = append(failures, err)
+				continue
+			}
+			msg.FallbackTemplate = repique.Action.FallbackTemplate
+
+			if err := p.waitJitter(ctx, repique.SendJitter, logger); err != nil {
+				return err
+			}
+
+			if err := p.send(ctx, state, msg, logger); err != nil {
+				if !errors.Is(err, errSendSkipped) {
+					logger.Error("failed to send on_expire message", "repique_id", repique.ID, "error", err)
+					failures = append(failures, err)
+				}
 				continue
 			}
 
@@ -104,11 +351,11 @@ func (p *Processor) handleExpiredJourney(
 		}
 
 		if repique.Action.EndJourney {
-			logger.Info("ending journey")
+			return p.endJourney(ctx, state, repique.ID, logger)
 		}
 	}
 
-	return nil
+	return errors.Join(failures...)
 }
 
 func (p *Processor) processLifecycleRepiques(
@@ -120,39 +367,71 @@ func (p *Processor) processLifecycleRepiques(
 ) error {
 	maxInactiveTime := cfg.Settings.MaxInactiveTime.ToDuration()
 
-	triggered := FindTriggeredLifecycleRepiques(
+	results := EvaluateLifecycleRepiques(
 		cfg.Settings.LifecycleRepiques,
 		attempts,
 		state,
 		maxInactiveTime,
 	)
+	p.recordDecisions(ctx, state, attempts, results, logger)
 
-	for _, result := range triggered {
+	var failures []error
+	for _, result := range triggeredOnly(results) {
 		repique := result.Repique
 
-		if repique.Action.Template == "" {
+		template := repique.Action.TemplateForAttempt(attempts.Attempts[repique.ID] + 1)
+		if template == "" {
+			continue
+		}
+
+		if p.isKilled(ctx, state.JourneyID, repique.ID, logger) ||
+			!p.isFlagEnabled(ctx, state, repique.ID, logger) ||
+			p.isQuotaReached(ctx, state, repique.ID, logger) ||
+			p.isCustomerCapReached(ctx, state, repique.ID, logger) {
 			continue
 		}
 
+		warnNearCap(result, attempts, logger)
+
 		logger.Info("lifecycle repique triggered",
 			"repique_id", repique.ID,
 			"reason", result.Reason,
 			"time_until_expiry", state.TimeUntilExpiry(maxInactiveTime),
 		)
 
-		msg := domain.NewMessage(state, repique.ID, repique.Action.Template, "")
+		msg, err := p.newMessage(ctx, state, repique.ID, template, "")
+		if err != nil {
+			logger.Error("failed to build lifecycle message", "repique_id", repique.ID, "error", err)
+			failures = append(failures, err)
+			continue
+		}
+		msg.FallbackTemplate = repique.Action.FallbackTemplate
+
+		if err := p.waitJitter(ctx, repique.SendJitter, logger); err != nil {
+			return err
+		}
 
-		if err := p.messenger.Send(ctx, msg); err != nil {
-			logger.Error("failed to send lifecycle message", "repique_id", repique.ID, "error", err)
+		if err := p.send(ctx, state, msg, logger); err != nil {
+			if !errors.Is(err, errSendSkipped) {
+				logger.Error("failed to send lifecycle message", "repique_id", repique.ID, "error", err)
+				failures = append(failures, err)
+			}
 			continue
 		}
 
 		if err := p.repository.IncrementRepiqueAttempt(ctx, state.JourneyID, state.CustomerNumber, repique.ID); err != nil {
 			logger.Error("failed to increment repique attempt", "repique_id", repique.ID, "error", err)
 		}
+
+		if repique.Action.EndJourney {
+			if err := p.endJourney(ctx, state, repique.ID, logger); err != nil {
+				return err
+			}
+			return errJourneyEnded
+		}
 	}
 
-	return nil
+	return errors.Join(failures...)
 }
 
 func (p *Processor) processStepRepiques(
@@ -168,32 +447,338 @@ func (p *Processor) processStepRepiques(
 		return nil
 	}
 
-	triggered := FindTriggeredStepRepiques(step.Repiques, attempts, state)
+	results := EvaluateStepRepiques(step.Repiques, attempts, state)
+	p.recordDecisions(ctx, state, attempts, results, logger)
 
-	for _, result := range triggered {
+	var failures []error
+	for _, result := range triggeredOnly(results) {
 		repique := result.Repique
 
-		if repique.Action.Template == "" {
+		template := repique.Action.TemplateForAttempt(attempts.Attempts[repique.ID] + 1)
+		if template == "" {
+			continue
+		}
+
+		if p.isKilled(ctx, state.JourneyID, repique.ID, logger) ||
+			!p.isFlagEnabled(ctx, state, repique.ID, logger) ||
+			p.isQuotaReached(ctx, state, repique.ID, logger) ||
+			p.isCustomerCapReached(ctx, state, repique.ID, logger) {
 			continue
 		}
 
+		warnNearCap(result, attempts, logger)
+
 		logger.Info("step repique triggered",
 			"repique_id", repique.ID,
 			"reason", result.Reason,
 			"time_in_step", state.TimeInStep(),
 		)
 
-		msg := domain.NewMessage(state, repique.ID, repique.Action.Template, state.Step)
+		msg, err := p.newMessage(ctx, state, repique.ID, template, state.Step)
+		if err != nil {
+			logger.Error("failed to build step message", "repique_id", repique.ID, "error", err)
+			failures = append(failures, err)
+			continue
+		}
+		msg.FallbackTemplate = repique.Action.FallbackTemplate
+
+		if err := p.waitJitter(ctx, repique.SendJitter, logger); err != nil {
+			return err
+		}
 
-		if err := p.messenger.Send(ctx, msg); err != nil {
-			logger.Error("failed to send step message", "repique_id", repique.ID, "error", err)
+		if err := p.send(ctx, state, msg, logger); err != nil {
+			if !errors.Is(err, errSendSkipped) {
+				logger.Error("failed to send step message", "repique_id", repique.ID, "error", err)
+				failures = append(failures, err)
+			}
 			continue
 		}
 
 		if err := p.repository.IncrementRepiqueAttempt(ctx, state.JourneyID, state.CustomerNumber, repique.ID); err != nil {
 			logger.Error("failed to increment repique attempt", "repique_id", repique.ID, "error", err)
 		}
+
+		if repique.Action.EndJourney {
+			if err := p.endJourney(ctx, state, repique.ID, logger); err != nil {
+				return err
+			}
+			return errJourneyEnded
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// lockCustomer acquires the customer's processing lock, polling until it is
+// free or the lock wait elapses.
+func (p *Processor) lockCustomer(ctx context.Context, customerNumber string) (func(context.Context) error, error) {
+	deadline := time.Now().Add(p.lockWait)
+	for {
+		release, err := p.locker.Acquire(ctx, customerNumber)
+		if !errors.Is(err, domain.ErrLockHeld) || !time.Now().Before(deadline) {
+			return release, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// waitJitter sleeps for a random duration within the repique's send jitter,
+// returning early with the context's error if it is cancelled. The sleep is
+// skipped when it would exceed the run's jitter budget or run into the
+// deadline margin.
+func (p *Processor) waitJitter(ctx context.Context, jitter *config.JitterRange, logger *slog.Logger) error {
+	delay := jitterDelay(jitter)
+	if delay <= 0 {
+		return nil
+	}
+
+	if p.jitterSpent+delay > p.jitterBudget {
+		logger.Debug("send jitter skipped", "reason", "run jitter budget spent", "delay", delay)
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline)-delay < p.deadlineMargin {
+		logger.Debug("send jitter skipped", "reason", "deadline near", "delay", delay)
+		return nil
+	}
+	p.jitterSpent += delay
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// jitterDelay picks a random delay within the jitter range, or zero without one.
+func jitterDelay(jitter *config.JitterRange) time.Duration {
+	if jitter == nil || jitter.MaxSeconds <= 0 {
+		return 0
+	}
+
+	minDelay := time.Duration(jitter.MinSeconds) * time.Second
+	maxDelay := time.Duration(jitter.MaxSeconds) * time.Second
+	return minDelay + time.Duration(rand.Int63n(int64(maxDelay-minDelay)+1))
+}
+
+// send sends a message and counts it against the tenant's daily quota and
+// the customer's daily cap.
+// Sends refused by the spend cap are logged as skips and reported as
+// errSendSkipped.
+func (p *Processor) send(ctx context.Context, state *domain.JourneyState, msg domain.Message, logger *slog.Logger) error {
+	if err := p.messenger.Send(ctx, msg); err != nil {
+		if errors.Is(err, domain.ErrSpendCapReached) {
+			logger.Info("repique skipped", "repique_id", msg.RepiqueID, "reason", "spend cap reached", "error", err)
+			return errSendSkipped
+		}
+		return err
+	}
+
+	p.publish(ctx, domain.NewLifecycleEvent(domain.EventRecoverySent, state, msg.RepiqueID), logger)
+
+	if p.tenantQuotas[state.TenantID] > 0 {
+		if _, err := p.repository.IncrementTenantSendCount(ctx, state.TenantID); err != nil {
+			logger.Warn("failed to record tenant send", "tenant_id", state.TenantID, "error", err)
+		}
+	}
+
+	if p.customerCap > 0 {
+		if _, err := p.repository.IncrementCustomerSendCount(ctx, state.CustomerNumber); err != nil {
+			logger.Warn("failed to record customer send", "error", err)
+		}
+	}
+	return nil
+}
+
+// newMessage builds the message for a repique. States from a light scan carry
+// no metadata, so the full state is loaded first.
+func (p *Processor) newMessage(
+	ctx context.Context,
+	state *domain.JourneyState,
+	repiqueID, template, step string,
+) (domain.Message, error) {
+	if state.Partial {
+		full, err := p.repository.GetJourneyState(ctx, state.JourneyID, state.CustomerNumber)
+		if err != nil {
+			return domain.Message{}, &domain.JourneyError{
+				JourneyID:      state.JourneyID,
+				CustomerNumber: state.CustomerNumber,
+				Op:             "GetJourneyState",
+				Err:            err,
+			}
+		}
+		*state = *full
+	}
+
+	return domain.NewMessage(state, repiqueID, template, step), nil
+}
+
+// isFlagEnabled reports whether the recovery.{journey}.{repique}.enabled flag
+// allows the repique for this customer. Evaluation failures are logged and
+// treated as enabled.
+func (p *Processor) isFlagEnabled(ctx context.Context, state *domain.JourneyState, repiqueID string, logger *slog.Logger) bool {
+	flag := fmt.Sprintf("recovery.%s.%s.enabled", state.JourneyID, repiqueID)
+
+	enabled, err := p.flags.IsEnabled(ctx, flag, state)
+	if err != nil {
+		logger.Warn("failed to evaluate feature flag", "flag", flag, "error", err)
+		return true
+	}
+	if !enabled {
+		logger.Info("repique skipped", "repique_id", repiqueID, "reason", "feature flag disabled", "flag", flag)
+	}
+	return enabled
+}
+
+// isQuotaReached reports whether the tenant's daily send quota is exhausted.
+// Lookup failures are logged and treated as not reached.
+func (p *Processor) isQuotaReached(ctx context.Context, state *domain.JourneyState, repiqueID string, logger *slog.Logger) bool {
+	quota := p.tenantQuotas[state.TenantID]
+	if quota <= 0 {
+		return false
+	}
+
+	sent, err := p.repository.GetTenantSendCount(ctx, state.TenantID)
+	if err != nil {
+		logger.Warn("failed to check tenant quota", "tenant_id", state.TenantID, "error", err)
+		return false
+	}
+	if sent < quota {
+		return false
+	}
+
+	p.quotaSkipped++
+	logger.Info("repique skipped",
+		"repique_id", repiqueID,
+		"reason", "tenant quota reached",
+		"tenant_id", state.TenantID,
+		"quota", quota,
+	)
+	return true
+}
+
+// isCustomerCapReached reports whether the customer has reached the daily cap
+// on sends across all journeys. Test customers are exempt. Lookup failures
+// are logged and treated as not reached.
+func (p *Processor) isCustomerCapReached(ctx context.Context, state *domain.JourneyState, repiqueID string, logger *slog.Logger) bool {
+	if p.customerCap <= 0 || p.testCustomers[state.CustomerNumber] {
+		return false
+	}
+
+	sent, err := p.repository.GetCustomerSendCount(ctx, state.CustomerNumber)
+	if err != nil {
+		logger.Warn("failed to check customer daily cap", "error", err)
+		return false
+	}
+	if sent < p.customerCap {
+		return false
+	}
+
+	p.capSkipped++
+	logger.Info("repique skipped",
+		"repique_id", repiqueID,
+		"reason", "customer daily cap reached",
+		"sent_today", sent,
+		"cap", p.customerCap,
+	)
+	return true
+}
+
+// QuotaSkipped returns the number of sends skipped because a tenant quota was reached.
+func (p *Processor) QuotaSkipped() int {
+	return p.quotaSkipped
+}
+
+// CustomerCapSkipped returns the number of sends skipped because a customer's
+// daily cap was reached.
+func (p *Processor) CustomerCapSkipped() int {
+	return p.capSkipped
+}
+
+// warnNearCap logs a warning when a send leaves one attempt before the repique's cap.
+func warnNearCap(result EvaluationResult, attempts *domain.RepiqueAttempts, logger *slog.Logger) {
+	if !result.NearCap {
+		return
+	}
+	logger.Warn("repique near attempt cap",
+		"repique_id", result.Repique.ID,
+		"attempt", attempts.Attempts[result.Repique.ID]+1,
+		"max_attempts", result.Repique.MaxAttempts,
+	)
+}
+
+// isKilled reports whether a repique has been disabled through its kill-switch.
+// Lookup failures are logged and treated as inactive so an unavailable
+// kill-switch store never blocks recovery.
+func (p *Processor) isKilled(ctx context.Context, journeyID, repiqueID string, logger *slog.Logger) bool {
+	active, err := p.killSwitch.IsActive(ctx, journeyID, repiqueID)
+	if err != nil {
+		logger.Warn("failed to check kill-switch", "repique_id", repiqueID, "error", err)
+		return false
+	}
+	if active {
+		logger.Info("repique skipped", "repique_id", repiqueID, "reason", "rule kill-switch active")
+	}
+	return active
+}
+
+// endJourney finishes a journey after a terminal repique by deleting its state,
+// so the customer is no longer scanned.
+func (p *Processor) endJourney(ctx context.Context, state *domain.JourneyState, repiqueID string, logger *slog.Logger) error {
+	if err := p.repository.DeleteJourneyState(ctx, state.JourneyID, state.CustomerNumber); err != nil {
+		return &domain.JourneyError{
+			JourneyID:      state.JourneyID,
+			CustomerNumber: state.CustomerNumber,
+			Op:             "DeleteJourneyState",
+			Err:            err,
+		}
 	}
 
+	logger.Info("journey ended", "repique_id", repiqueID)
+	p.publish(ctx, domain.NewLifecycleEvent(domain.EventJourneyFinished, state, repiqueID), logger)
 	return nil
 }
+
+// publish publishes a lifecycle event. Failures are logged and never affect
+// processing.
+func (p *Processor) publish(ctx context.Context, event domain.LifecycleEvent, logger *slog.Logger) {
+	if err := p.events.Publish(ctx, event); err != nil {
+		logger.Warn("failed to publish lifecycle event", "event_type", event.Type, "error", err)
+	}
+}
+
+// recordDecisions writes every evaluation result to the decision log.
+// Failures are logged and never block processing.
+func (p *Processor) recordDecisions(
+	ctx context.Context,
+	state *domain.JourneyState,
+	attempts *domain.RepiqueAttempts,
+	results []EvaluationResult,
+	logger *slog.Logger,
+) {
+	now := time.Now()
+	for _, result := range results {
+		err := p.decisions.Record(ctx, domain.Decision{
+			JourneyID:      state.JourneyID,
+			CustomerNumber: state.CustomerNumber,
+			Step:           state.Step,
+			RepiqueID:      result.Repique.ID,
+			Attempt:        attempts.Attempts[result.Repique.ID] + 1,
+			Triggered:      result.ShouldTrigger,
+			Reason:         result.Reason,
+			Inactivity:     state.TimeSinceLastInteraction(),
+			EvaluatedAt:    now,
+		})
+		if err != nil {
+			logger.Warn("failed to record decision", "repique_id", result.Repique.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"worker-project/internal/adapters/decisions"
+	"worker-project/internal/adapters/events"
+	"worker-project/internal/adapters/featureflags"
+	"worker-project/internal/config"
+	"worker-project/internal/domain"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestJitterDelayWithinBand(t *testing.T) {
+	jitter := &config.JitterRange{MinSeconds: 5, MaxSeconds: 10}
+
+	for i := 0; i < 1000; i++ {
+		if delay := jitterDelay(jitter); delay < 5*time.Second || delay > 10*time.Second {
+			t.Fatalf("delay %s outside [5s, 10s]", delay)
+		}
+	}
+
+	if delay := jitterDelay(nil); delay != 0 {
+		t.Fatalf("delay without jitter = %s, want 0", delay)
+	}
+}
+
+func TestWaitJitterDelaysSend(t *testing.T) {
+	p := NewProcessor(nil, nil, nil, nil, nil, nil, nil, ProcessorConfig{MaxRunJitter: time.Minute}, discardLogger())
+	jitter := &config.JitterRange{MinSeconds: 1, MaxSeconds: 1}
+
+	start := time.Now()
+	if err := p.waitJitter(context.Background(), jitter, discardLogger()); err != nil {
+		t.Fatalf("waitJitter: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 2*time.Second {
+		t.Fatalf("send delayed %s, want about 1s", elapsed)
+	}
+}
+
+func TestWaitJitterSkipsSleep(t *testing.T) {
+	jitter := &config.JitterRange{MinSeconds: 30, MaxSeconds: 30}
+
+	tests := []struct {
+		name    string
+		cfg     ProcessorConfig
+		spent   time.Duration
+		timeout time.Duration
+	}{
+		{name: "jitter disabled", cfg: ProcessorConfig{}},
+		{name: "run budget spent", cfg: ProcessorConfig{MaxRunJitter: time.Minute}, spent: 45 * time.Second},
+		{name: "deadline near", cfg: ProcessorConfig{MaxRunJitter: time.Minute, DeadlineMargin: 30 * time.Second}, timeout: 50 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcessor(nil, nil, nil, nil, nil, nil, nil, tt.cfg, discardLogger())
+			p.jitterSpent = tt.spent
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			if err := p.waitJitter(ctx, jitter, discardLogger()); err != nil {
+				t.Fatalf("waitJitter: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("slept %s, want no delay", elapsed)
+			}
+			if p.jitterSpent != tt.spent {
+				t.Fatalf("jitter spent = %s, want %s", p.jitterSpent, tt.spent)
+			}
+		})
+	}
+}
+
+// memoryRepository is an in-memory ports.StateRepository holding repique
+// attempts and daily customer send counts.
+type memoryRepository struct {
+	attempts      map[string]int
+	customerSends map[string]int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{attempts: make(map[string]int), customerSends: make(map[string]int)}
+}
+
+func (r *memoryRepository) GetJourneyState(context.Context, string, string) (*domain.JourneyState, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryRepository) GetRepiqueAttempts(context.Context, string, string) (*domain.RepiqueAttempts, error) {
+	attempts := domain.NewRepiqueAttempts()
+	for id, n := range r.attempts {
+		attempts.Attempts[id] = n
+	}
+	return attempts, nil
+}
+
+func (r *memoryRepository) IncrementRepiqueAttempt(_ context.Context, _, _, repiqueID string) error {
+	r.attempts[repiqueID]++
+	return nil
+}
+
+func (r *memoryRepository) DeleteJourneyState(context.Context, string, string) error {
+	return nil
+}
+
+func (r *memoryRepository) IncrementFailureCount(context.Context, string, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) ResetFailureCount(context.Context, string, string) error {
+	return nil
+}
+
+func (r *memoryRepository) IncrementTenantSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) GetTenantSendCount(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryRepository) IncrementCustomerSendCount(_ context.Context, customerNumber string) (int, error) {
+	r.customerSends[customerNumber]++
+	return r.customerSends[customerNumber], nil
+}
+
+func (r *memoryRepository) GetCustomerSendCount(_ context.Context, customerNumber string) (int, error) {
+	return r.customerSends[customerNumber], nil
+}
+
+func (r *memoryRepository) DeadLetterJourney(context.Context, string, string) error {
+	return nil
+}
+
+// recordingMessenger records the messages it is asked to send.
+type recordingMessenger struct {
+	sent []domain.Message
+}
+
+func (m *recordingMessenger) Send(_ context.Context, msg domain.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// inactiveKillSwitch never disables a repique.
+type inactiveKillSwitch struct{}
+
+func (inactiveKillSwitch) IsActive(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func TestProcessJourneyTestCustomersBypassCaps(t *testing.T) {
+	const (
+		testCustomer   = "5511000000001"
+		normalCustomer = "5511999990000"
+	)
+	cfg := &config.JourneyConfig{
+		Journey:  config.Journey{ID: "checkout"},
+		Settings: config.Settings{MaxInactiveTime: config.Duration{Minutes: 24 * 60}},
+		Steps: []config.Step{{
+			ID: "cart",
+			Repiques: []config.Repique{{
+				ID:          "nudge",
+				MaxAttempts: 1,
+				Condition:   config.Condition{TimeInStep: &config.TimeCondition{GteMinutes: 60}},
+				Action:      config.Action{Template: "journey.checkout.templates:nudge"},
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		customer  string
+		attempts  int
+		sentToday int
+		wantSent  bool
+	}{
+		{name: "test customer at attempt cap", customer: testCustomer, attempts: 1, wantSent: true},
+		{name: "test customer at daily cap", customer: testCustomer, sentToday: 5, wantSent: true},
+		{name: "normal customer at attempt cap", customer: normalCustomer, attempts: 1},
+		{name: "normal customer at daily cap", customer: normalCustomer, sentToday: 5},
+		{name: "normal customer under caps", customer: normalCustomer, wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			repo.attempts["nudge"] = tt.attempts
+			repo.customerSends[tt.customer] = tt.sentToday
+			messenger := &recordingMessenger{}
+
+			p := NewProcessor(repo, messenger, inactiveKillSwitch{}, featureflags.NoopEvaluator{}, events.NoopPublisher{}, decisions.NoopLog{}, nil,
+				ProcessorConfig{TestCustomers: []string{testCustomer}, CustomerDailyCap: 3}, discardLogger())
+
+			state := &domain.JourneyState{JourneyID: "checkout", CustomerNumber: tt.customer, Step: "cart"}
+			state.ForceAge(90 * time.Minute)
+
+			if err := p.ProcessJourney(context.Background(), cfg, state); err != nil {
+				t.Fatalf("ProcessJourney: %v", err)
+			}
+			if sent := len(messenger.sent) > 0; sent != tt.wantSent {
+				t.Fatalf("message sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package timewindow
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock is a wall-clock time of day, in minutes since midnight.
+type Clock int
+
+// ParseClock parses a "HH:MM" time of day.
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM): %w", s, err)
+	}
+	return Clock(t.Hour()*60 + t.Minute()), nil
+}
+
+// ClockOf returns the wall-clock time of day of t in its own location.
+func ClockOf(t time.Time) Clock {
+	return Clock(t.Hour()*60 + t.Minute())
+}
+
+// String formats the clock as "HH:MM".
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// Window is a daily time-of-day range [Start, End) evaluated in Location.
+// An End before Start wraps past midnight (e.g. 22:00-07:00), and an End
+// equal to Start covers the whole day.
+//
+// Bounds are wall-clock times, so a window keeps its local hours across DST
+// transitions; on the day clocks change it is an hour longer or shorter in
+// absolute time.
+type Window struct {
+	Start    Clock
+	End      Clock
+	Location *time.Location
+}
+
+// New creates a window from "HH:MM" bounds and an IANA timezone name.
+// An empty timezone means UTC.
+func New(start, end, timezone string) (Window, error) {
+	startClock, err := ParseClock(start)
+	if err != nil {
+		return Window{}, err
+	}
+	endClock, err := ParseClock(end)
+	if err != nil {
+		return Window{}, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return Window{Start: startClock, End: endClock, Location: loc}, nil
+}
+
+// Contains reports whether t falls within the window.
+func (w Window) Contains(t time.Time) bool {
+	c := ClockOf(t.In(w.location()))
+	switch {
+	case w.Start == w.End:
+		return true
+	case w.Start < w.End:
+		return c >= w.Start && c < w.End
+	default:
+		return c >= w.Start || c < w.End
+	}
+}
+
+// String formats the window as "HH:MM-HH:MM Location".
+func (w Window) String() string {
+	return fmt.Sprintf("%s-%s %s", w.Start, w.End, w.location())
+}
+
+func (w Window) location() *time.Location {
+	if w.Location == nil {
+		return time.UTC
+	}
+	return w.Location
+}
@@ -0,0 +1,105 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+)
+
+func mustWindow(t *testing.T, start, end, timezone string) Window {
+	t.Helper()
+	w, err := New(start, end, timezone)
+	if err != nil {
+		t.Fatalf("New(%q, %q, %q): %v", start, end, timezone, err)
+	}
+	return w
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestWindowContains(t *testing.T) {
+	tests := []struct {
+		name   string
+		window Window
+		at     time.Time
+		want   bool
+	}{
+		// New York is UTC-5 in January.
+		{name: "within a same-day window", window: mustWindow(t, "09:00", "18:00", "America/New_York"), at: utc("2026-01-15T16:00:00Z"), want: true},
+		{name: "start is inclusive", window: mustWindow(t, "09:00", "18:00", "America/New_York"), at: utc("2026-01-15T14:00:00Z"), want: true},
+		{name: "end is exclusive", window: mustWindow(t, "09:00", "18:00", "America/New_York"), at: utc("2026-01-15T23:00:00Z"), want: false},
+		{name: "evaluated in the window's timezone", window: mustWindow(t, "09:00", "18:00", "America/New_York"), at: utc("2026-01-15T09:30:00Z"), want: false},
+
+		{name: "wrap: before midnight", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-01-16T04:30:00Z"), want: true},
+		{name: "wrap: after midnight", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-01-16T11:59:00Z"), want: true},
+		{name: "wrap: start is inclusive", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-01-16T03:00:00Z"), want: true},
+		{name: "wrap: end is exclusive", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-01-16T12:00:00Z"), want: false},
+		{name: "wrap: midday", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-01-16T17:00:00Z"), want: false},
+
+		{name: "start equals end at midnight", window: mustWindow(t, "00:00", "00:00", "America/New_York"), at: utc("2026-01-16T17:00:00Z"), want: true},
+		{name: "start equals end mid-day", window: mustWindow(t, "08:00", "08:00", "America/New_York"), at: utc("2026-01-16T12:59:00Z"), want: true},
+
+		// 2026-03-08: clocks jump from 02:00 EST to 03:00 EDT at 07:00 UTC.
+		{name: "spring forward: before the jump", window: mustWindow(t, "01:00", "03:00", "America/New_York"), at: utc("2026-03-08T06:59:00Z"), want: true},
+		{name: "spring forward: window ends at the jump", window: mustWindow(t, "01:00", "03:00", "America/New_York"), at: utc("2026-03-08T07:00:00Z"), want: false},
+		{name: "spring forward: wrap keeps local end", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-03-08T10:59:00Z"), want: true},
+		{name: "spring forward: wrap ends at local 07:00", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-03-08T11:00:00Z"), want: false},
+
+		// 2026-11-01: clocks fall back from 02:00 EDT to 01:00 EST at 06:00 UTC.
+		{name: "fall back: first 01:30", window: mustWindow(t, "01:00", "02:00", "America/New_York"), at: utc("2026-11-01T05:30:00Z"), want: true},
+		{name: "fall back: repeated 01:30", window: mustWindow(t, "01:00", "02:00", "America/New_York"), at: utc("2026-11-01T06:30:00Z"), want: true},
+		{name: "fall back: window ends at 02:00 EST", window: mustWindow(t, "01:00", "02:00", "America/New_York"), at: utc("2026-11-01T07:00:00Z"), want: false},
+		{name: "fall back: wrap keeps local end", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-11-01T11:59:00Z"), want: true},
+		{name: "fall back: wrap ends at local 07:00", window: mustWindow(t, "22:00", "07:00", "America/New_York"), at: utc("2026-11-01T12:00:00Z"), want: false},
+
+		{name: "nil location is UTC", window: Window{Start: 9 * 60, End: 17 * 60}, at: utc("2026-01-15T09:00:00Z"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.at); got != tt.want {
+				t.Errorf("%s.Contains(%s) = %v, want %v", tt.window, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{name: "valid", start: "22:00", end: "07:00", timezone: "America/New_York", want: "22:00-07:00 America/New_York"},
+		{name: "empty timezone is UTC", start: "09:00", end: "17:30", want: "09:00-17:30 UTC"},
+		{name: "invalid start", start: "9am", end: "17:00", wantErr: true},
+		{name: "invalid end", start: "09:00", end: "24:00", wantErr: true},
+		{name: "unknown timezone", start: "09:00", end: "17:00", timezone: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := New(tt.start, tt.end, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got window %s", w)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("window = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}